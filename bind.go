@@ -0,0 +1,236 @@
+package gocfg
+
+import (
+	"encoding"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Bind registers a variable for every struct field with an `env` tag. It
+// requires a pointer to the struct to assign values after parsing.
+//
+// Supported tags:
+// - env: variable name
+// - default: default value, converted to the field type
+// - required: "true" if the variable is required
+// - separator: separator of slice elements and map pairs
+// - kvseparator: separator of map keys and values
+// - sensitive: "true" if the value should be masked
+// - file: "true" if the value could be read from the NAME_FILE file
+// - description: variable description
+//
+// Fields which implement Decoder or encoding.TextUnmarshaler are registered
+// as custom types. Other nested and embedded structs are walked recursively. An `env` tag on a
+// struct field is used as a prefix for the names of its fields. Nil pointer
+// fields are allocated, allocated values are assigned to fields only if no
+// error is returned.
+//
+// It returns error if:
+// - ptr is not a pointer to a struct
+// - tagged field has unsupported type
+// - default or required tag has a wrong value
+//
+// Variables are added only if all fields are valid, so neither the config
+// nor the struct is changed if error is returned.
+func (c *Config) Bind(ptr interface{}) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind requires a non-nil pointer to a struct, got %T", ptr)
+	}
+	// fields are registered in a staging config first
+	staged := &Config{}
+	var allocs []func()
+	if err := staged.bindStruct(v.Elem(), "", &allocs); err != nil {
+		return err
+	}
+	for _, alloc := range allocs {
+		alloc()
+	}
+	for _, setting := range staged.variables {
+		c.setVariable(setting)
+	}
+	return nil
+}
+
+// bindStruct registers variables for the struct fields. Values of nil
+// pointer fields are allocated, funcs which assign them to the fields are
+// added to allocs.
+func (c *Config) bindStruct(v reflect.Value, prefix string, allocs *[]func()) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+		// unexported fields can't be assigned, but exported fields of
+		// embedded structs can
+		if field.PkgPath != "" && !(field.Anonymous && fv.Kind() == reflect.Struct) {
+			continue
+		}
+		name, tagged := field.Tag.Lookup("env")
+		if fv.Kind() == reflect.Ptr {
+			if !tagged && fv.Type().Elem().Kind() != reflect.Struct {
+				continue
+			}
+			if fv.IsNil() {
+				ptr, n := fv, reflect.New(fv.Type().Elem())
+				*allocs = append(*allocs, func() { ptr.Set(n) })
+				fv = n
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct && !isValueStruct(fv.Type()) {
+			p := prefix
+			if tagged && name != "" {
+				p += name + "_"
+			}
+			if err := c.bindStruct(fv, p, allocs); err != nil {
+				return err
+			}
+			continue
+		}
+		if !tagged {
+			continue
+		}
+		if err := c.bindField(fv, field, prefix+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindField registers variable for the struct field.
+func (c *Config) bindField(fv reflect.Value, field reflect.StructField, name string) error {
+	setting := &Variable{
+		Name:              name,
+		Separator:         field.Tag.Get("separator"),
+		KeyValueSeparator: field.Tag.Get("kvseparator"),
+		Description:       field.Tag.Get("description"),
+	}
+	if r, ok := field.Tag.Lookup("required"); ok {
+		required, err := strconv.ParseBool(r)
+		if err != nil {
+			return fmt.Errorf("field '%s' has a wrong required tag value '%s'", field.Name, r)
+		}
+		setting.Required = required
+	}
+	if sv, ok := field.Tag.Lookup("sensitive"); ok {
+		sensitive, err := strconv.ParseBool(sv)
+		if err != nil {
+			return fmt.Errorf("field '%s' has a wrong sensitive tag value '%s'", field.Name, sv)
+		}
+		setting.Sensitive = sensitive
+	}
+	if f, ok := field.Tag.Lookup("file"); ok {
+		allowFile, err := strconv.ParseBool(f)
+		if err != nil {
+			return fmt.Errorf("field '%s' has a wrong file tag value '%s'", field.Name, f)
+		}
+		setting.AllowFile = allowFile
+	}
+	if !c.setPointer(fv.Addr().Interface(), setting) {
+		return fmt.Errorf("field '%s' has unsupported type %s", field.Name, fv.Type())
+	}
+	if d, ok := field.Tag.Lookup("default"); ok {
+		dv, err := convertValue(setting, setting.valueType, d)
+		if err != nil {
+			return fmt.Errorf("field '%s' has a wrong default tag value '%s'", field.Name, displayValue(setting, d))
+		}
+		setting.Default = dv
+	}
+	return nil
+}
+
+// setPointer adds variable to config choosing the setter by the pointer
+// type. It returns false if the type is not supported.
+func (c *Config) setPointer(pointer interface{}, setting *Variable) bool {
+	switch p := pointer.(type) {
+	case *string:
+		c.SetString(p, setting)
+	case *Secret:
+		c.SetSecret(p, setting)
+	case *url.URL:
+		c.SetURL(p, setting)
+	case *net.IP:
+		c.SetIP(p, setting)
+	case *net.IPNet:
+		c.SetIPNet(p, setting)
+	case *int:
+		c.SetInt(p, setting)
+	case *int64:
+		c.SetInt64(p, setting)
+	case *int8:
+		c.SetInt8(p, setting)
+	case *int16:
+		c.SetInt16(p, setting)
+	case *int32:
+		c.SetInt32(p, setting)
+	case *uint:
+		c.SetUint(p, setting)
+	case *uint8:
+		c.SetUint8(p, setting)
+	case *uint16:
+		c.SetUint16(p, setting)
+	case *uint32:
+		c.SetUint32(p, setting)
+	case *uint64:
+		c.SetUint64(p, setting)
+	case *float32:
+		c.SetFloat32(p, setting)
+	case *float64:
+		c.SetFloat64(p, setting)
+	case *bool:
+		c.SetBool(p, setting)
+	case *time.Duration:
+		c.SetDuration(p, setting)
+	case *[]string:
+		c.SetStringSlice(p, setting)
+	case *[]int:
+		c.SetIntSlice(p, setting)
+	case *[]int64:
+		c.SetInt64Slice(p, setting)
+	case *[]float32:
+		c.SetFloat32Slice(p, setting)
+	case *[]float64:
+		c.SetFloat64Slice(p, setting)
+	case *[]bool:
+		c.SetBoolSlice(p, setting)
+	case *[]time.Duration:
+		c.SetDurationSlice(p, setting)
+	case *map[string]string:
+		c.SetStringMap(p, setting)
+	case *map[string]int:
+		c.SetIntMap(p, setting)
+	case *map[string]int64:
+		c.SetInt64Map(p, setting)
+	case *map[string]float32:
+		c.SetFloat32Map(p, setting)
+	case *map[string]float64:
+		c.SetFloat64Map(p, setting)
+	case *map[string]bool:
+		c.SetBoolMap(p, setting)
+	case *map[string]time.Duration:
+		c.SetDurationMap(p, setting)
+	case Decoder:
+		c.SetVar(p, setting)
+	case encoding.TextUnmarshaler:
+		c.SetText(p, setting)
+	default:
+		return false
+	}
+	return true
+}
+
+// isValueStruct returns true if the struct type is a single value, like
+// url.URL, or pointer to the type implements Decoder or
+// encoding.TextUnmarshaler.
+func isValueStruct(t reflect.Type) bool {
+	if t == reflect.TypeOf(url.URL{}) || t == reflect.TypeOf(net.IPNet{}) {
+		return true
+	}
+	pt := reflect.PtrTo(t)
+	return pt.Implements(reflect.TypeOf((*Decoder)(nil)).Elem()) ||
+		pt.Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem())
+}
@@ -0,0 +1,136 @@
+package gocfg
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bindDBConfig struct {
+	Host string `env:"HOST" default:"localhost"`
+	Port int    `env:"PORT" default:"5432"`
+}
+
+type bindTracing struct {
+	TracingEnabled bool `env:"TRACING_ENABLED"`
+}
+
+type bindAppConfig struct {
+	bindTracing
+	APIURL    string         `env:"API_URL" required:"true"`
+	Timeout   int64          `env:"REQUEST_TIMEOUT" default:"30"`
+	CPULimit  float32        `env:"CPU_LIMIT" default:"1.5"`
+	Load      float64        `env:"LOAD_AVERAGE_THRESHOLD"`
+	Idle      time.Duration  `env:"IDLE_TIMEOUT" default:"1m"`
+	HTTPPort  uint16         `env:"HTTP_PORT" default:"8080"`
+	BatchSize *int           `env:"BATCH_SIZE" default:"500"`
+	Brokers   []string       `env:"KAFKA_BROKERS" default:"a:9092;b:9092" separator:";"`
+	Limits    map[string]int `env:"TENANT_LIMITS" default:"acme:100" kvseparator:":"`
+	LogLevel  logLevel       `env:"LOG_LEVEL" default:"info"`
+	Region    region         `env:"REGION"`
+	DB        bindDBConfig   `env:"DB"`
+	Replica   *bindDBConfig  `env:"REPLICA"`
+	Ignored   string
+	internal  string `env:"INTERNAL"`
+}
+
+func TestConfigBind(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"API_URL":                "https://api.example.com",
+			"LOAD_AVERAGE_THRESHOLD": "0.8",
+			"TRACING_ENABLED":        "true",
+			"DB_HOST":                "db.example.com",
+			"REPLICA_PORT":           "6432",
+			"INTERNAL":               "value",
+			"REGION":                 "eu",
+		},
+	}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	var appCfg bindAppConfig
+	assert.Nil(t, cfg.Bind(&appCfg))
+	assert.Nil(t, cfg.Parse())
+
+	batchSize := 500
+	expected := bindAppConfig{
+		bindTracing: bindTracing{TracingEnabled: true},
+		APIURL:      "https://api.example.com",
+		Timeout:     30,
+		CPULimit:    1.5,
+		Load:        0.8,
+		Idle:        time.Minute,
+		HTTPPort:    8080,
+		BatchSize:   &batchSize,
+		Brokers:     []string{"a:9092", "b:9092"},
+		Limits:      map[string]int{"acme": 100},
+		LogLevel:    1,
+		Region:      region{"eu"},
+		DB:          bindDBConfig{Host: "db.example.com", Port: 5432},
+		Replica:     &bindDBConfig{Host: "localhost", Port: 6432},
+	}
+	assert.Equal(t, expected, appCfg)
+}
+
+func TestConfigBindMissingRequired(t *testing.T) {
+	cfg := New()
+	cfg.SetEnvLookuper(&EnvLookuperMock{})
+	var appCfg struct {
+		APIURL string `env:"API_URL" required:"true"`
+	}
+	assert.Nil(t, cfg.Bind(&appCfg))
+	assert.Equal(t, NewParseErrors(&MissingError{Name: "API_URL"}), cfg.Parse())
+}
+
+func TestConfigBindErrors(t *testing.T) {
+	var value struct{}
+	var unsupported struct {
+		Values map[int]int `env:"VALUES"`
+	}
+	var wrongDefault struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT" default:"http"`
+	}
+	var wrongSensitiveDefault struct {
+		Pin int `env:"PIN" default:"s3cr3t" sensitive:"true"`
+	}
+	var wrongRequired struct {
+		Port int `env:"PORT" required:"yes"`
+	}
+
+	testcases := []struct {
+		name string
+		ptr  interface{}
+		err  error
+	}{
+		{"not a pointer", value, errors.New("bind requires a non-nil pointer to a struct, got struct {}")},
+		{"unsupported type", &unsupported, errors.New("field 'Values' has unsupported type map[int]int")},
+		{"wrong default tag", &wrongDefault, errors.New("field 'Port' has a wrong default tag value 'http'")},
+		{"wrong sensitive default tag", &wrongSensitiveDefault, errors.New("field 'Pin' has a wrong default tag value '******'")},
+		{"wrong required tag", &wrongRequired, errors.New("field 'Port' has a wrong required tag value 'yes'")},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		err := cfg.Bind(tc.ptr)
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s", tc.name))
+		// no variables are added if binding failed
+		assert.Empty(t, cfg.variables, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestConfigBindErrorKeepsStruct(t *testing.T) {
+	var value struct {
+		Replica   *bindDBConfig `env:"REPLICA"`
+		BatchSize *int          `env:"BATCH_SIZE"`
+		Values    map[int]int   `env:"VALUES"`
+	}
+	cfg := New()
+	assert.Equal(t, errors.New("field 'Values' has unsupported type map[int]int"), cfg.Bind(&value))
+	// nil pointers are not allocated if binding failed
+	assert.Nil(t, value.Replica)
+	assert.Nil(t, value.BatchSize)
+}
@@ -0,0 +1,135 @@
+package gocfg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationErrors holds all failures of the variable validators. Adding it
+// to ParseErrors adds every failure separately.
+type ValidationErrors []error
+
+// Error implements Error method of error interface.
+func (ve ValidationErrors) Error() string {
+	return strings.Join(errorMessages(ve), ", ")
+}
+
+// add adds error to the list. ValidationErrors are flattened.
+func (ve *ValidationErrors) add(err error) {
+	if errs, ok := err.(ValidationErrors); ok {
+		for _, e := range errs {
+			ve.add(e)
+		}
+		return
+	}
+	*ve = append(*ve, err)
+}
+
+// err returns nil if there are no errors, the error itself if there is a
+// single one and ValidationErrors otherwise.
+func (ve ValidationErrors) err() error {
+	switch len(ve) {
+	case 0:
+		return nil
+	case 1:
+		return ve[0]
+	}
+	return ve
+}
+
+// All returns validation func which runs every validation func and
+// collects all their failures.
+func All(funcs ...func(value interface{}) error) func(value interface{}) error {
+	f := func(value interface{}) error {
+		var errs ValidationErrors
+		for _, f := range funcs {
+			if err := f(value); err != nil {
+				errs.add(err)
+			}
+		}
+		return errs.err()
+	}
+	// All is described by descriptions of all validation funcs
+	info := &validatorInfo{}
+	for _, f := range funcs {
+		fi := lookupValidator(f)
+		info.descs = append(info.descs, fi.descs...)
+		info.partial = info.partial || fi.partial
+		if fi.keywords != nil {
+			if info.keywords == nil {
+				info.keywords = map[string]interface{}{}
+			}
+			mergeKeywords(info.keywords, fi.keywords)
+		}
+	}
+	return withInfo(info, f)
+}
+
+// Any returns validation func which succeeds if at least one of validation
+// funcs succeeds. Otherwise it returns error listing all failures.
+func Any(funcs ...func(value interface{}) error) func(value interface{}) error {
+	f := func(value interface{}) error {
+		var errs ValidationErrors
+		for _, f := range funcs {
+			err := f(value)
+			if err == nil {
+				return nil
+			}
+			errs.add(err)
+		}
+		if len(errs) == 0 {
+			return nil
+		}
+		return fmt.Errorf("value '%s' does not pass any validation: %s", formatValue(&Variable{}, value), strings.Join(errorMessages(errs), "; "))
+	}
+	descs := make([]string, len(funcs))
+	anyOf := make([]interface{}, len(funcs))
+	// alternatives without keywords pass any value in JSON Schema
+	expressible := true
+	for i, f := range funcs {
+		fi := lookupValidator(f)
+		descs[i] = strings.Join(fi.descs, ", ")
+		anyOf[i] = fi.keywords
+		expressible = expressible && fi.keywords != nil
+	}
+	var keywords map[string]interface{}
+	if expressible {
+		keywords = map[string]interface{}{"anyOf": anyOf}
+	}
+	return Describe("any of ("+strings.Join(descs, "; ")+")", keywords, f)
+}
+
+// Not returns validation func which fails if validation func f succeeds.
+func Not(f func(value interface{}) error) func(value interface{}) error {
+	fi := lookupValidator(f)
+	var keywords map[string]interface{}
+	if !fi.partial {
+		keywords = map[string]interface{}{"not": fi.keywords}
+	}
+	return Describe("not ("+strings.Join(fi.descs, ", ")+")", keywords, func(value interface{}) error {
+		if f(value) == nil {
+			return fmt.Errorf("value '%s' is not allowed", formatValue(&Variable{}, value))
+		}
+		return nil
+	})
+}
+
+// When returns validation func which runs validation func f only if cond
+// returns true for the value.
+func When(cond func(value interface{}) bool, f func(value interface{}) error) func(value interface{}) error {
+	return Describe("conditional ("+strings.Join(describeValidator(f), ", ")+")", nil, func(value interface{}) error {
+		if !cond(value) {
+			return nil
+		}
+		return f(value)
+	})
+}
+
+// errorMessages returns messages of errors.
+func errorMessages(errs []error) []string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return msgs
+}
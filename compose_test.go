@@ -0,0 +1,81 @@
+package gocfg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateComposeFuncs(t *testing.T) {
+	isHTTPS := func(value interface{}) bool {
+		return strings.HasPrefix(value.(string), "https")
+	}
+
+	testcases := []struct {
+		name  string
+		value interface{}
+		vfunc func(value interface{}) error
+		err   error
+	}{
+		{"check all func", "https://api.example.com", All(ValidateStringHasPrefix("https"), ValidateStringContains("example.com")), nil},
+		{"check all func one failed", "https://api.example.org", All(ValidateStringHasPrefix("https"), ValidateStringContains("example.com")), errors.New("value 'https://api.example.org' does not contain 'example.com'")},
+		{"check all func all failed", "http://api.example.org", All(ValidateStringHasPrefix("https"), ValidateStringContains("example.com")), ValidationErrors{
+			errors.New("value 'http://api.example.org' does not start with 'https'"),
+			errors.New("value 'http://api.example.org' does not contain 'example.com'"),
+		}},
+		{"check all func nested", "http://api.example.org", All(All(ValidateStringHasPrefix("https"), ValidateStringHasSuffix("com")), ValidateStringContains("example.com")), ValidationErrors{
+			errors.New("value 'http://api.example.org' does not start with 'https'"),
+			errors.New("value 'http://api.example.org' does not end with 'com'"),
+			errors.New("value 'http://api.example.org' does not contain 'example.com'"),
+		}},
+		{"check any func", "http://api.example.com", Any(ValidateStringHasPrefix("https"), ValidateStringHasSuffix("com")), nil},
+		{"check any func failed", "http://api.example.org", Any(ValidateStringHasPrefix("https"), ValidateStringHasSuffix("com")), errors.New("value 'http://api.example.org' does not pass any validation: value 'http://api.example.org' does not start with 'https'; value 'http://api.example.org' does not end with 'com'")},
+		{"check not func", "https://api.example.com", Not(ValidateStringContains("localhost")), nil},
+		{"check not func failed", "https://localhost", Not(ValidateStringContains("localhost")), errors.New("value 'https://localhost' is not allowed")},
+		{"check not func numeric", 8080, Not(ValidateBetween(0, 1023)), nil},
+		{"check when func", "https://api.example.com", When(isHTTPS, ValidateStringHasSuffix("com")), nil},
+		{"check when func skipped", "http://api.example.org", When(isHTTPS, ValidateStringHasSuffix("com")), nil},
+		{"check when func failed", "https://api.example.org", When(isHTTPS, ValidateStringHasSuffix("com")), errors.New("value 'https://api.example.org' does not end with 'com'")},
+	}
+	for _, tc := range testcases {
+		err := tc.vfunc(tc.value)
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestConfigValidators(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"API_URL":  "http://api.example.org",
+			"PASSWORD": "secret",
+			"PORTS":    "80,8080,443",
+		},
+	}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	var apiURL, password string
+	var ports []int
+	cfg.SetString(&apiURL, &Variable{
+		Name:           "API_URL",
+		ValidationFunc: ValidateStringHasPrefix("https"),
+		Validators:     []func(value interface{}) error{ValidateStringContains("example.com"), ValidateStringHasSuffix("org")},
+	})
+	cfg.SetString(&password, &Variable{
+		Name:       "PASSWORD",
+		Sensitive:  true,
+		Validators: []func(value interface{}) error{All(ValidateStringHasPrefix("s3"), ValidateStringContains("!"))},
+	})
+	cfg.SetIntSlice(&ports, &Variable{Name: "PORTS", ElementValidationFunc: ValidateMin(1024)})
+	err := cfg.Parse()
+	assert.Equal(t, NewParseErrors(
+		&ValidationError{Name: "API_URL", Value: "http://api.example.org", Err: errors.New("value 'http://api.example.org' does not start with 'https'")},
+		&ValidationError{Name: "API_URL", Value: "http://api.example.org", Err: errors.New("value 'http://api.example.org' does not contain 'example.com'")},
+		&ValidationError{Name: "PASSWORD", Value: RedactedValue, Err: &redactedError{msg: "variable 'PASSWORD' value '******' is not valid", kind: ErrValidation}},
+		&ValidationError{Name: "PASSWORD", Value: RedactedValue, Err: &redactedError{msg: "variable 'PASSWORD' value '******' is not valid", kind: ErrValidation}},
+		&ValidationError{Name: "PORTS", Value: "80,8080,443", Err: errors.New("value '80' is less than '1024'")},
+		&ValidationError{Name: "PORTS", Value: "80,8080,443", Err: errors.New("value '443' is less than '1024'")},
+	), err)
+}
@@ -1,8 +1,18 @@
 package gocfg
 
 import (
+	"encoding"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 // valueType represents valid config types
@@ -16,14 +26,72 @@ const (
 	FLOAT32
 	FLOAT64
 	BOOL
+	DURATION
+	SLICE
+	MAP
+	INT8
+	INT16
+	INT32
+	UINT
+	UINT8
+	UINT16
+	UINT32
+	UINT64
+	TEXT
+	DECODER
+	SECRET
+	URL
+	IP
+	IPNET
 )
 
+// valueTypeNames maps value types to their names.
+var valueTypeNames = map[valueType]string{
+	STRING:   "string",
+	INT:      "int",
+	INT64:    "int64",
+	FLOAT32:  "float32",
+	FLOAT64:  "float64",
+	BOOL:     "bool",
+	DURATION: "duration",
+	SLICE:    "slice",
+	MAP:      "map",
+	INT8:     "int8",
+	INT16:    "int16",
+	INT32:    "int32",
+	UINT:     "uint",
+	UINT8:    "uint8",
+	UINT16:   "uint16",
+	UINT32:   "uint32",
+	UINT64:   "uint64",
+	TEXT:     "text",
+	DECODER:  "decoder",
+	SECRET:   "secret",
+	URL:      "url",
+	IP:       "ip",
+	IPNET:    "cidr",
+}
+
+// String returns the value type name.
+func (t valueType) String() string {
+	if n, ok := valueTypeNames[t]; ok {
+		return n
+	}
+	return fmt.Sprintf("valueType(%d)", t)
+}
+
 // EnvLookuper represents app environment variables. It helps
 // to avoid manipulations with real environment and mock it in tests.
 type EnvLookuper interface {
 	LookupEnv(key string) (string, bool)
 }
 
+// Decoder is implemented by custom types which can decode themselves from
+// the environment variable value.
+type Decoder interface {
+	Decode(value string) error
+}
+
 // EnvLookuperImpl implements EnvLookuper interface.
 type EnvLookuperImpl struct{}
 
@@ -38,29 +106,109 @@ type Variable struct {
 	Name           string
 	Required       bool
 	ValidationFunc func(value interface{}) error
-	pointer        interface{}
-	valueType      valueType
+	// Description is shown in usage, flags help and generated docs.
+	Description string
+	// Validators are run after ValidationFunc. Failures of all validators
+	// are reported, not only the first one.
+	Validators []func(value interface{}) error
+	// Sensitive masks the value in errors and any other output of Config.
+	Sensitive bool
+	// AllowBasePrefix enables 0x, 0o and 0b prefixed integer values. They
+	// are parsed by strconv with base 0.
+	AllowBasePrefix bool
+	// DurationUnit is a unit of DURATION values defined as bare integers.
+	// It's time.Second if not set.
+	DurationUnit time.Duration
+	// Separator splits SLICE values into elements and MAP values into
+	// key/value pairs. It's "," if not set.
+	Separator string
+	// KeyValueSeparator splits MAP pairs into key and value. It's "=" if
+	// not set.
+	KeyValueSeparator string
+	// TrimSpace removes leading and trailing white space of SLICE elements,
+	// MAP keys and values.
+	TrimSpace bool
+	// SkipEmpty drops empty SLICE elements and MAP pairs instead of parsing
+	// them.
+	SkipEmpty bool
+	// AllowFile enables reading the value from the file referenced by
+	// NAME_FILE variable if NAME is not defined, e.g. DB_PASSWORD_FILE.
+	// Trailing newlines are trimmed.
+	AllowFile bool
+	// ElementValidationFunc validates every SLICE element and MAP value,
+	// ValidationFunc validates the whole slice or map.
+	ElementValidationFunc func(value interface{}) error
+	pointer               interface{}
+	valueType             valueType
+	elemType              valueType
+	// source is a name of the source which supplied the value
+	source string
+	// raw is the value before conversion, value is the value assigned on
+	// the last Parse(). It's nil if the variable was not set.
+	raw   string
+	value interface{}
+	// failed is true if the variable failed to parse on the last Parse()
+	failed bool
+	// store copies the value to the Handle after successful Parse() or
+	// Reload(). It's nil if the variable has no handle.
+	store func()
 }
 
 // Config manages variables lookup and validation.
 type Config struct {
-	variables []*Variable
-	env       EnvLookuper
+	variables   []*Variable
+	rules       []*rule
+	sources     []*source
+	flags       *flag.FlagSet
+	args        []string
+	maxFileSize int64
+	strict      bool
+	help        io.Writer
+	// mu guards pointers and parsing state of variables, reloadMu
+	// serializes Parse() and Reload()
+	mu            sync.RWMutex
+	reloadMu      sync.Mutex
+	onChange      map[string][]ChangeFunc
+	onReloadError func(err error)
 }
 
-// New returns new Config object.
+// New returns new Config object. It lookups variables in the process
+// environment, which is the SourceEnv source.
 func New() *Config {
-	return &Config{env: &EnvLookuperImpl{}}
+	c := &Config{maxFileSize: DefaultMaxFileSize}
+	c.AddSource(SourceEnv, &EnvLookuperImpl{}, 0)
+	return c
 }
 
-// SetEnvLookuper sets default EnvLookuper. Check tests for examples.
+// SetEnvLookuper sets EnvLookuper of the SourceEnv source. Check tests for
+// examples.
 func (c *Config) SetEnvLookuper(l EnvLookuper) {
-	c.env = l
+	for _, s := range c.sources {
+		if s.name == SourceEnv {
+			s.lookuper = l
+			return
+		}
+	}
+	c.AddSource(SourceEnv, l, 0)
+}
+
+// SetStrictMode enables checking of default values when variables are
+// added. In strict mode setters panic if the default value has a wrong type
+// or is not valid, so the mistake is caught by unit tests and not at
+// deploy time.
+func (c *Config) SetStrictMode(strict bool) {
+	c.strict = strict
 }
 
-// setVariable adds variable to config.
+// setVariable adds variable to config. It panics in strict mode if the
+// default value has a wrong type or is not valid.
 func (c *Config) setVariable(setting *Variable) {
 	formatEnvVarName(&setting.Name)
+	if c.strict && setting.Default != nil {
+		if _, err := checkDefault(setting); err != nil {
+			panic(fmt.Errorf("gocfg: %w", err))
+		}
+	}
 	c.variables = append(c.variables, setting)
 }
 
@@ -88,6 +236,70 @@ func (c *Config) SetInt64(pointer *int64, setting *Variable) {
 	c.setVariable(setting)
 }
 
+// SetInt8 adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetInt8(pointer *int8, setting *Variable) {
+	setting.valueType = INT8
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetInt16 adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetInt16(pointer *int16, setting *Variable) {
+	setting.valueType = INT16
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetInt32 adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetInt32(pointer *int32, setting *Variable) {
+	setting.valueType = INT32
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetUint adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetUint(pointer *uint, setting *Variable) {
+	setting.valueType = UINT
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetUint8 adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetUint8(pointer *uint8, setting *Variable) {
+	setting.valueType = UINT8
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetUint16 adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetUint16(pointer *uint16, setting *Variable) {
+	setting.valueType = UINT16
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetUint32 adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetUint32(pointer *uint32, setting *Variable) {
+	setting.valueType = UINT32
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetUint64 adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetUint64(pointer *uint64, setting *Variable) {
+	setting.valueType = UINT64
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
 // SetFloat32 adds variable to config. It requiers a pointer to the
 // go variable to assign value after parsing.
 func (c *Config) SetFloat32(pointer *float32, setting *Variable) {
@@ -112,6 +324,221 @@ func (c *Config) SetBool(pointer *bool, setting *Variable) {
 	c.setVariable(setting)
 }
 
+// SetDuration adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetDuration(pointer *time.Duration, setting *Variable) {
+	setting.valueType = DURATION
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetSecret adds sensitive variable to config. It requiers a pointer to the
+// go variable to assign value after parsing. ValidationFunc receives the
+// value as a string.
+func (c *Config) SetSecret(pointer *Secret, setting *Variable) {
+	setting.valueType = SECRET
+	setting.Sensitive = true
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetURL adds variable to config. It requiers a pointer to the go variable
+// to assign value after parsing. Value should be an absolute URL. Default
+// value could be a string, url.URL or *url.URL.
+func (c *Config) SetURL(pointer *url.URL, setting *Variable) {
+	setting.valueType = URL
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetIP adds variable to config. It requiers a pointer to the go variable
+// to assign value after parsing. Default value could be a string or net.IP.
+func (c *Config) SetIP(pointer *net.IP, setting *Variable) {
+	setting.valueType = IP
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetIPNet adds variable to config. It requiers a pointer to the go variable
+// to assign value after parsing. Value should be in CIDR notation, e.g.
+// 10.0.0.0/8. Default value could be a string, net.IPNet or *net.IPNet.
+func (c *Config) SetIPNet(pointer *net.IPNet, setting *Variable) {
+	setting.valueType = IPNET
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetText adds variable of custom type to config. It requiers a pointer to
+// the go variable which implements encoding.TextUnmarshaler. Default value
+// could be a string, which is unmarshaled, or a value of the custom type.
+func (c *Config) SetText(pointer encoding.TextUnmarshaler, setting *Variable) {
+	setting.valueType = TEXT
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetVar adds variable of custom type to config. It requiers a pointer to
+// the go variable which implements Decoder. Default value could be a string,
+// which is decoded, or a value of the custom type.
+func (c *Config) SetVar(pointer Decoder, setting *Variable) {
+	setting.valueType = DECODER
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// setSlice adds slice variable to config.
+func (c *Config) setSlice(pointer interface{}, elemType valueType, setting *Variable) {
+	setting.valueType = SLICE
+	setting.elemType = elemType
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetStringSlice adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetStringSlice(pointer *[]string, setting *Variable) {
+	c.setSlice(pointer, STRING, setting)
+}
+
+// SetIntSlice adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetIntSlice(pointer *[]int, setting *Variable) {
+	c.setSlice(pointer, INT, setting)
+}
+
+// SetInt64Slice adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetInt64Slice(pointer *[]int64, setting *Variable) {
+	c.setSlice(pointer, INT64, setting)
+}
+
+// SetFloat32Slice adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetFloat32Slice(pointer *[]float32, setting *Variable) {
+	c.setSlice(pointer, FLOAT32, setting)
+}
+
+// SetFloat64Slice adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetFloat64Slice(pointer *[]float64, setting *Variable) {
+	c.setSlice(pointer, FLOAT64, setting)
+}
+
+// SetBoolSlice adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetBoolSlice(pointer *[]bool, setting *Variable) {
+	c.setSlice(pointer, BOOL, setting)
+}
+
+// SetDurationSlice adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetDurationSlice(pointer *[]time.Duration, setting *Variable) {
+	c.setSlice(pointer, DURATION, setting)
+}
+
+// setMap adds map variable to config.
+func (c *Config) setMap(pointer interface{}, elemType valueType, setting *Variable) {
+	setting.valueType = MAP
+	setting.elemType = elemType
+	setting.pointer = pointer
+	c.setVariable(setting)
+}
+
+// SetStringMap adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetStringMap(pointer *map[string]string, setting *Variable) {
+	c.setMap(pointer, STRING, setting)
+}
+
+// SetIntMap adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetIntMap(pointer *map[string]int, setting *Variable) {
+	c.setMap(pointer, INT, setting)
+}
+
+// SetInt64Map adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetInt64Map(pointer *map[string]int64, setting *Variable) {
+	c.setMap(pointer, INT64, setting)
+}
+
+// SetFloat32Map adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetFloat32Map(pointer *map[string]float32, setting *Variable) {
+	c.setMap(pointer, FLOAT32, setting)
+}
+
+// SetFloat64Map adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetFloat64Map(pointer *map[string]float64, setting *Variable) {
+	c.setMap(pointer, FLOAT64, setting)
+}
+
+// SetBoolMap adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetBoolMap(pointer *map[string]bool, setting *Variable) {
+	c.setMap(pointer, BOOL, setting)
+}
+
+// SetDurationMap adds variable to config. It requiers a pointer to the
+// go variable to assign value after parsing.
+func (c *Config) SetDurationMap(pointer *map[string]time.Duration, setting *Variable) {
+	c.setMap(pointer, DURATION, setting)
+}
+
+// formatValue returns string representation of the variable value, which
+// could be parsed back.
+func formatValue(setting *Variable, value interface{}) string {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice:
+		if setting.valueType != SLICE {
+			break
+		}
+		sep := setting.Separator
+		if sep == "" {
+			sep = defaultSeparator
+		}
+		elems := make([]string, v.Len())
+		for i := range elems {
+			elems[i] = formatValue(setting, v.Index(i).Interface())
+		}
+		return strings.Join(elems, sep)
+	case reflect.Map:
+		sep, kvSep := setting.Separator, setting.KeyValueSeparator
+		if sep == "" {
+			sep = defaultSeparator
+		}
+		if kvSep == "" {
+			kvSep = defaultKeyValueSeparator
+		}
+		pairs := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			pairs = append(pairs, iter.Key().String()+kvSep+formatValue(setting, iter.Value().Interface()))
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, sep)
+	}
+	switch t := value.(type) {
+	case encoding.TextMarshaler:
+		if b, err := t.MarshalText(); err == nil {
+			return string(b)
+		}
+	case fmt.Stringer:
+		return t.String()
+	}
+	// types like url.URL implement fmt.Stringer with pointer receiver
+	if v.IsValid() {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		if s, ok := p.Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+	}
+	return fmt.Sprint(value)
+}
+
 func formatEnvVarName(name *string) {
 	*name = strings.ReplaceAll(*name, "-", "_")
 	*name = strings.ToUpper(*name)
@@ -3,7 +3,11 @@ package gocfg
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -36,9 +40,9 @@ func TestConfigString(t *testing.T) {
 	}{
 		{"basic test case", &Variable{Name: "LOG_LEVEL"}, nil, "DEBUG"},
 		{"default value", &Variable{Name: "LOG_FORMAT", Default: "JSON"}, nil, "JSON"},
-		{"wrong default value type", &Variable{Name: "LOG_FORMAT", Default: 1}, NewParseErrors(errors.New("variable 'LOG_FORMAT' has a wrong default value type")), ""},
+		{"wrong default value type", &Variable{Name: "LOG_FORMAT", Default: 1}, NewParseErrors(&DefaultTypeError{Name: "LOG_FORMAT", Type: "string"}), ""},
 		{"default value conflict", &Variable{Name: "LOG_LEVEL", Default: "WARN"}, nil, "DEBUG"},
-		{"missing required var", &Variable{Name: "LOGG_LVL", Required: true}, NewParseErrors(errors.New("'LOGG_LVL' variable is missing")), ""},
+		{"missing required var", &Variable{Name: "LOGG_LVL", Required: true}, NewParseErrors(&MissingError{Name: "LOGG_LVL"}), ""},
 		{"unformated variable name", &Variable{Name: "api-version"}, nil, "/v5"},
 	}
 
@@ -70,10 +74,11 @@ func TestConfigInt(t *testing.T) {
 	}{
 		{"basic test case", &Variable{Name: "REQUEST_TIMEOUT"}, nil, 30},
 		{"default value", &Variable{Name: "BATCH_SIZE", Default: 500}, nil, 500},
-		{"wrong default value type", &Variable{Name: "BATCH_SIZE", Default: "1"}, NewParseErrors(errors.New("variable 'BATCH_SIZE' has a wrong default value type")), 0},
+		{"wrong default value type", &Variable{Name: "BATCH_SIZE", Default: "1"}, NewParseErrors(&DefaultTypeError{Name: "BATCH_SIZE", Type: "int"}), 0},
 		{"default value conflict", &Variable{Name: "REQUEST_TIMEOUT", Default: 5}, nil, 30},
-		{"missing required var", &Variable{Name: "BATCH_SIZE", Required: true}, NewParseErrors(errors.New("'BATCH_SIZE' variable is missing")), 0},
+		{"missing required var", &Variable{Name: "BATCH_SIZE", Required: true}, NewParseErrors(&MissingError{Name: "BATCH_SIZE"}), 0},
 		{"unformated variable name", &Variable{Name: "api-version"}, nil, 5},
+		{"default value validation", &Variable{Name: "BATCH_SIZE", Default: 5000, ValidationFunc: ValidateMax(1000)}, NewParseErrors(&ValidationError{Name: "BATCH_SIZE", Value: "5000", Err: errors.New("value '5000' is greater than '1000'")}), 5000},
 	}
 
 	for _, tc := range testcases {
@@ -103,9 +108,9 @@ func TestConfigInt64(t *testing.T) {
 	}{
 		{"basic test case", &Variable{Name: "REQUEST_TIMEOUT"}, nil, 30},
 		{"default value", &Variable{Name: "BATCH_SIZE", Default: int64(500)}, nil, 500},
-		{"wrong default value type", &Variable{Name: "BATCH_SIZE", Default: "1"}, NewParseErrors(errors.New("variable 'BATCH_SIZE' has a wrong default value type")), 0},
+		{"wrong default value type", &Variable{Name: "BATCH_SIZE", Default: "1"}, NewParseErrors(&DefaultTypeError{Name: "BATCH_SIZE", Type: "int64"}), 0},
 		{"default value conflict", &Variable{Name: "REQUEST_TIMEOUT", Default: 5}, nil, 30},
-		{"missing required var", &Variable{Name: "BATCH_SIZE", Required: true}, NewParseErrors(errors.New("'BATCH_SIZE' variable is missing")), 0},
+		{"missing required var", &Variable{Name: "BATCH_SIZE", Required: true}, NewParseErrors(&MissingError{Name: "BATCH_SIZE"}), 0},
 		{"unformated variable name", &Variable{Name: "api-version"}, nil, 5},
 	}
 
@@ -136,9 +141,9 @@ func TestConfigFloat32(t *testing.T) {
 	}{
 		{"basic test case", &Variable{Name: "CPU_REQUEST"}, nil, 1.2},
 		{"default value", &Variable{Name: "CPU_LIMIT", Default: float32(1.8)}, nil, 1.8},
-		{"wrong default value type", &Variable{Name: "CPU_LIMIT", Default: "1"}, NewParseErrors(errors.New("variable 'CPU_LIMIT' has a wrong default value type")), 0},
+		{"wrong default value type", &Variable{Name: "CPU_LIMIT", Default: "1"}, NewParseErrors(&DefaultTypeError{Name: "CPU_LIMIT", Type: "float32"}), 0},
 		{"default value conflict", &Variable{Name: "LOAD_AVERAGE_THRESHOLD", Default: 2.2}, nil, 0.8},
-		{"missing required var", &Variable{Name: "CPU_LIMIT", Required: true}, NewParseErrors(errors.New("'CPU_LIMIT' variable is missing")), 0},
+		{"missing required var", &Variable{Name: "CPU_LIMIT", Required: true}, NewParseErrors(&MissingError{Name: "CPU_LIMIT"}), 0},
 		{"unformated variable name", &Variable{Name: "cpu-request"}, nil, 1.2},
 	}
 
@@ -169,9 +174,9 @@ func TestConfigFloat64(t *testing.T) {
 	}{
 		{"basic test case", &Variable{Name: "CPU_REQUEST"}, nil, 1.2},
 		{"default value", &Variable{Name: "CPU_LIMIT", Default: float64(1.8)}, nil, 1.8},
-		{"wrong default value type", &Variable{Name: "CPU_LIMIT", Default: "1"}, NewParseErrors(errors.New("variable 'CPU_LIMIT' has a wrong default value type")), 0},
+		{"wrong default value type", &Variable{Name: "CPU_LIMIT", Default: "1"}, NewParseErrors(&DefaultTypeError{Name: "CPU_LIMIT", Type: "float64"}), 0},
 		{"default value conflict", &Variable{Name: "LOAD_AVERAGE_THRESHOLD", Default: 2.2}, nil, 0.8},
-		{"missing required var", &Variable{Name: "CPU_LIMIT", Required: true}, NewParseErrors(errors.New("'CPU_LIMIT' variable is missing")), 0},
+		{"missing required var", &Variable{Name: "CPU_LIMIT", Required: true}, NewParseErrors(&MissingError{Name: "CPU_LIMIT"}), 0},
 		{"unformated variable name", &Variable{Name: "cpu-request"}, nil, 1.2},
 	}
 
@@ -202,9 +207,9 @@ func TestConfigBool(t *testing.T) {
 	}{
 		{"basic test case", &Variable{Name: "TRACING_ENABLED"}, nil, true},
 		{"default value", &Variable{Name: "USE_S3_STORAGE", Default: true}, nil, true},
-		{"wrong default value type", &Variable{Name: "USE_S3_STORAGE", Default: "NO"}, NewParseErrors(errors.New("variable 'USE_S3_STORAGE' has a wrong default value type")), false},
+		{"wrong default value type", &Variable{Name: "USE_S3_STORAGE", Default: "NO"}, NewParseErrors(&DefaultTypeError{Name: "USE_S3_STORAGE", Type: "bool"}), false},
 		{"default value conflict", &Variable{Name: "USE_GCS_STORAGE", Default: true}, nil, false},
-		{"missing required var", &Variable{Name: "USE_S3_STORAGE", Required: true}, NewParseErrors(errors.New("'USE_S3_STORAGE' variable is missing")), false},
+		{"missing required var", &Variable{Name: "USE_S3_STORAGE", Required: true}, NewParseErrors(&MissingError{Name: "USE_S3_STORAGE"}), false},
 		{"unformated variable name", &Variable{Name: "tracing-enabled"}, nil, true},
 	}
 
@@ -218,3 +223,566 @@ func TestConfigBool(t *testing.T) {
 		assert.Equal(t, value, tc.value, fmt.Sprintf("Test case: %s", tc.name))
 	}
 }
+
+func TestConfigDuration(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"REQUEST_TIMEOUT": "1m30s",
+			"IDLE_TIMEOUT":    "30",
+			"READ_TIMEOUT":    "fast",
+			"RETENTION":       "3000000",
+		},
+	}
+
+	_, durationErr := time.ParseDuration("fast")
+
+	testcases := []struct {
+		name     string
+		variable *Variable
+		err      error
+		value    time.Duration
+	}{
+		{"basic test case", &Variable{Name: "REQUEST_TIMEOUT"}, nil, 90 * time.Second},
+		{"bare integer", &Variable{Name: "IDLE_TIMEOUT"}, nil, 30 * time.Second},
+		{"bare integer with unit", &Variable{Name: "IDLE_TIMEOUT", DurationUnit: time.Millisecond}, nil, 30 * time.Millisecond},
+		{"bare integer overflow", &Variable{Name: "RETENTION", DurationUnit: time.Hour}, NewParseErrors(&TypeError{Name: "RETENTION", Value: "3000000", Type: "time.Duration", Err: strconv.ErrRange}), 0},
+		{"wrong value type", &Variable{Name: "READ_TIMEOUT"}, NewParseErrors(&TypeError{Name: "READ_TIMEOUT", Value: "fast", Type: "time.Duration", Err: durationErr}), 0},
+		{"default value", &Variable{Name: "WRITE_TIMEOUT", Default: 5 * time.Second}, nil, 5 * time.Second},
+		{"wrong default value type", &Variable{Name: "WRITE_TIMEOUT", Default: 5}, NewParseErrors(&DefaultTypeError{Name: "WRITE_TIMEOUT", Type: "time.Duration"}), 0},
+		{"default value conflict", &Variable{Name: "REQUEST_TIMEOUT", Default: time.Second}, nil, 90 * time.Second},
+		{"missing required var", &Variable{Name: "WRITE_TIMEOUT", Required: true}, NewParseErrors(&MissingError{Name: "WRITE_TIMEOUT"}), 0},
+		{"unformated variable name", &Variable{Name: "request-timeout"}, nil, 90 * time.Second},
+		{"validation", &Variable{Name: "REQUEST_TIMEOUT", ValidationFunc: func(value interface{}) error {
+			if value.(time.Duration) > time.Minute {
+				return errors.New("timeout is too long")
+			}
+			return nil
+		}}, NewParseErrors(&ValidationError{Name: "REQUEST_TIMEOUT", Value: "1m30s", Err: errors.New("timeout is too long")}), 90 * time.Second},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(env)
+		var value time.Duration
+		cfg.SetDuration(&value, tc.variable)
+		err := cfg.Parse()
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s, Error: '%+v'", tc.name, err))
+		assert.Equal(t, value, tc.value, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestConfigStringSlice(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"KAFKA_BROKERS":   "a:9092,b:9092",
+			"ALLOWED_ORIGINS": " https://a.example.com ; ;https://b.example.com",
+			"EMPTY_LIST":      "",
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		variable *Variable
+		err      error
+		value    []string
+	}{
+		{"basic test case", &Variable{Name: "KAFKA_BROKERS"}, nil, []string{"a:9092", "b:9092"}},
+		{"custom separator", &Variable{Name: "ALLOWED_ORIGINS", Separator: ";"}, nil, []string{" https://a.example.com ", " ", "https://b.example.com"}},
+		{"trim and skip empty", &Variable{Name: "ALLOWED_ORIGINS", Separator: ";", TrimSpace: true, SkipEmpty: true}, nil, []string{"https://a.example.com", "https://b.example.com"}},
+		{"empty value", &Variable{Name: "EMPTY_LIST"}, nil, []string{}},
+		{"default value", &Variable{Name: "TOPICS", Default: []string{"events"}}, nil, []string{"events"}},
+		{"wrong default value type", &Variable{Name: "TOPICS", Default: "events"}, NewParseErrors(&DefaultTypeError{Name: "TOPICS", Type: "[]string"}), nil},
+		{"missing required var", &Variable{Name: "TOPICS", Required: true}, NewParseErrors(&MissingError{Name: "TOPICS"}), nil},
+		{"element validation", &Variable{Name: "KAFKA_BROKERS", ElementValidationFunc: ValidateStringHasPrefix("a")}, NewParseErrors(&ValidationError{Name: "KAFKA_BROKERS", Value: "a:9092,b:9092", Err: errors.New("value 'b:9092' does not start with 'a'")}), []string{"a:9092", "b:9092"}},
+		{"slice validation", &Variable{Name: "KAFKA_BROKERS", ValidationFunc: func(value interface{}) error {
+			if len(value.([]string)) < 3 {
+				return errors.New("at least 3 brokers are required")
+			}
+			return nil
+		}}, NewParseErrors(&ValidationError{Name: "KAFKA_BROKERS", Value: "a:9092,b:9092", Err: errors.New("at least 3 brokers are required")}), []string{"a:9092", "b:9092"}},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(env)
+		var value []string
+		cfg.SetStringSlice(&value, tc.variable)
+		err := cfg.Parse()
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s, Error: '%+v'", tc.name, err))
+		assert.Equal(t, value, tc.value, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestConfigIntSlice(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"PORTS":       "80, 443",
+			"EMPTY_PORTS": "80,,443",
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		variable *Variable
+		err      error
+		value    []int
+	}{
+		{"trim space", &Variable{Name: "PORTS", TrimSpace: true}, nil, []int{80, 443}},
+		{"wrong value type", &Variable{Name: "PORTS"}, NewParseErrors(&TypeError{Name: "PORTS", Value: " 443", Type: "int", Err: strconv.ErrSyntax}), nil},
+		{"empty element", &Variable{Name: "EMPTY_PORTS"}, NewParseErrors(&TypeError{Name: "EMPTY_PORTS", Value: "", Type: "int", Err: strconv.ErrSyntax}), nil},
+		{"skip empty element", &Variable{Name: "EMPTY_PORTS", SkipEmpty: true}, nil, []int{80, 443}},
+		{"default value", &Variable{Name: "ADMIN_PORTS", Default: []int{8080}}, nil, []int{8080}},
+		{"wrong default value type", &Variable{Name: "ADMIN_PORTS", Default: []int64{8080}}, NewParseErrors(&DefaultTypeError{Name: "ADMIN_PORTS", Type: "[]int"}), nil},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(env)
+		var value []int
+		cfg.SetIntSlice(&value, tc.variable)
+		err := cfg.Parse()
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s, Error: '%+v'", tc.name, err))
+		assert.Equal(t, value, tc.value, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestConfigOtherSlices(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"IDS":      "1,2",
+			"WEIGHTS":  "0.5,1.5",
+			"FLAGS":    "true,false",
+			"TIMEOUTS": "1s,2m",
+		},
+	}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	var (
+		ids       []int64
+		weights32 []float32
+		weights64 []float64
+		flags     []bool
+		timeouts  []time.Duration
+	)
+	cfg.SetInt64Slice(&ids, &Variable{Name: "IDS"})
+	cfg.SetFloat32Slice(&weights32, &Variable{Name: "WEIGHTS"})
+	cfg.SetFloat64Slice(&weights64, &Variable{Name: "WEIGHTS"})
+	cfg.SetBoolSlice(&flags, &Variable{Name: "FLAGS"})
+	cfg.SetDurationSlice(&timeouts, &Variable{Name: "TIMEOUTS"})
+	assert.Nil(t, cfg.Parse())
+	assert.Equal(t, []int64{1, 2}, ids)
+	assert.Equal(t, []float32{0.5, 1.5}, weights32)
+	assert.Equal(t, []float64{0.5, 1.5}, weights64)
+	assert.Equal(t, []bool{true, false}, flags)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Minute}, timeouts)
+}
+
+func TestConfigStringMap(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"EXTRA_HEADERS":       "X-Env=prod,X-Team=core",
+			"RESOURCE_ATTRIBUTES": "service.name: api ; ; service.version: 1.2",
+			"MALFORMED_HEADERS":   "X-Env=prod,X-Team",
+			"DUPLICATE_HEADERS":   "X-Env=prod,X-Env=dev",
+			"EMPTY_HEADERS":       "",
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		variable *Variable
+		err      error
+		value    map[string]string
+	}{
+		{"basic test case", &Variable{Name: "EXTRA_HEADERS"}, nil, map[string]string{"X-Env": "prod", "X-Team": "core"}},
+		{"custom separators", &Variable{Name: "RESOURCE_ATTRIBUTES", Separator: ";", KeyValueSeparator: ":", TrimSpace: true, SkipEmpty: true}, nil, map[string]string{"service.name": "api", "service.version": "1.2"}},
+		{"empty value", &Variable{Name: "EMPTY_HEADERS"}, nil, map[string]string{}},
+		{"malformed pair", &Variable{Name: "MALFORMED_HEADERS"}, NewParseErrors(&TypeError{Name: "MALFORMED_HEADERS", Value: "X-Team", Type: "map[string]string", Err: &mapError{"a malformed pair 'X-Team'"}}), nil},
+		{"duplicate key", &Variable{Name: "DUPLICATE_HEADERS"}, NewParseErrors(&TypeError{Name: "DUPLICATE_HEADERS", Value: "X-Env=dev", Type: "map[string]string", Err: &mapError{"a duplicate key 'X-Env'"}}), nil},
+		{"default value", &Variable{Name: "DEFAULT_HEADERS", Default: map[string]string{"X-Env": "dev"}}, nil, map[string]string{"X-Env": "dev"}},
+		{"wrong default value type", &Variable{Name: "DEFAULT_HEADERS", Default: "X-Env=dev"}, NewParseErrors(&DefaultTypeError{Name: "DEFAULT_HEADERS", Type: "map[string]string"}), nil},
+		{"missing required var", &Variable{Name: "DEFAULT_HEADERS", Required: true}, NewParseErrors(&MissingError{Name: "DEFAULT_HEADERS"}), nil},
+		{"value validation", &Variable{Name: "EXTRA_HEADERS", ElementValidationFunc: ValidateStringHasPrefix("p")}, NewParseErrors(&ValidationError{Name: "EXTRA_HEADERS", Value: "X-Env=prod,X-Team=core", Err: errors.New("value 'core' does not start with 'p'")}), map[string]string{"X-Env": "prod", "X-Team": "core"}},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(env)
+		var value map[string]string
+		cfg.SetStringMap(&value, tc.variable)
+		err := cfg.Parse()
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s, Error: '%+v'", tc.name, err))
+		assert.Equal(t, value, tc.value, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestConfigIntMap(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"TENANT_LIMITS":       "acme=100,globex=50",
+			"WRONG_TENANT_LIMITS": "acme=100,globex=many",
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		variable *Variable
+		err      error
+		value    map[string]int
+	}{
+		{"basic test case", &Variable{Name: "TENANT_LIMITS"}, nil, map[string]int{"acme": 100, "globex": 50}},
+		{"wrong value type", &Variable{Name: "WRONG_TENANT_LIMITS"}, NewParseErrors(&TypeError{Name: "WRONG_TENANT_LIMITS", Value: "many", Type: "int", Err: strconv.ErrSyntax}), nil},
+		{"default value", &Variable{Name: "DEFAULT_LIMITS", Default: map[string]int{"acme": 10}}, nil, map[string]int{"acme": 10}},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(env)
+		var value map[string]int
+		cfg.SetIntMap(&value, tc.variable)
+		err := cfg.Parse()
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s, Error: '%+v'", tc.name, err))
+		assert.Equal(t, value, tc.value, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestConfigUint16(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"HTTP_PORT":     "8080",
+			"HEX_PORT":      "0x1F90",
+			"NEGATIVE_PORT": "-1",
+			"HUGE_PORT":     "70000",
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		variable *Variable
+		err      error
+		value    uint16
+	}{
+		{"basic test case", &Variable{Name: "HTTP_PORT"}, nil, 8080},
+		{"default value", &Variable{Name: "GRPC_PORT", Default: uint16(9090)}, nil, 9090},
+		{"wrong default value type", &Variable{Name: "GRPC_PORT", Default: 9090}, NewParseErrors(&DefaultTypeError{Name: "GRPC_PORT", Type: "uint16"}), 0},
+		{"hex value without base prefix", &Variable{Name: "HEX_PORT"}, NewParseErrors(&TypeError{Name: "HEX_PORT", Value: "0x1F90", Type: "uint16", Err: strconv.ErrSyntax}), 0},
+		{"hex value with base prefix", &Variable{Name: "HEX_PORT", AllowBasePrefix: true}, nil, 8080},
+		{"negative value", &Variable{Name: "NEGATIVE_PORT"}, NewParseErrors(&TypeError{Name: "NEGATIVE_PORT", Value: "-1", Type: "uint16", Err: strconv.ErrSyntax}), 0},
+		{"out of range", &Variable{Name: "HUGE_PORT"}, NewParseErrors(&TypeError{Name: "HUGE_PORT", Value: "70000", Type: "uint16", Err: strconv.ErrRange}), 0},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(env)
+		var value uint16
+		cfg.SetUint16(&value, tc.variable)
+		err := cfg.Parse()
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s, Error: '%+v'", tc.name, err))
+		assert.Equal(t, value, tc.value, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestConfigIntegerWidths(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"SMALL":   "-128",
+			"MEDIUM":  "-32768",
+			"LARGE":   "-2147483648",
+			"WORKERS": "8",
+			"MASK":    "0b1010",
+			"BITS":    "0o17",
+			"BIG":     "18446744073709551615",
+			"TOO_BIG": "128",
+		},
+	}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	var (
+		i8  int8
+		i16 int16
+		i32 int32
+		u   uint
+		u8  uint8
+		u32 uint32
+		u64 uint64
+		big int8
+	)
+	cfg.SetInt8(&i8, &Variable{Name: "SMALL"})
+	cfg.SetInt16(&i16, &Variable{Name: "MEDIUM"})
+	cfg.SetInt32(&i32, &Variable{Name: "LARGE"})
+	cfg.SetUint(&u, &Variable{Name: "WORKERS"})
+	cfg.SetUint8(&u8, &Variable{Name: "MASK", AllowBasePrefix: true})
+	cfg.SetUint32(&u32, &Variable{Name: "BITS", AllowBasePrefix: true})
+	cfg.SetUint64(&u64, &Variable{Name: "BIG"})
+	cfg.SetInt8(&big, &Variable{Name: "TOO_BIG"})
+	err := cfg.Parse()
+	assert.Equal(t, NewParseErrors(&TypeError{Name: "TOO_BIG", Value: "128", Type: "int8", Err: strconv.ErrRange}), err)
+	assert.Equal(t, int8(-128), i8)
+	assert.Equal(t, int16(-32768), i16)
+	assert.Equal(t, int32(-2147483648), i32)
+	assert.Equal(t, uint(8), u)
+	assert.Equal(t, uint8(10), u8)
+	assert.Equal(t, uint32(15), u32)
+	assert.Equal(t, uint64(18446744073709551615), u64)
+}
+
+// logLevel implements encoding.TextUnmarshaler.
+type logLevel int
+
+func (l *logLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	case "error":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown log level '%s'", text)
+	}
+	return nil
+}
+
+// region implements Decoder.
+type region struct {
+	Code string
+}
+
+func (r *region) Decode(value string) error {
+	if len(value) != 2 {
+		return fmt.Errorf("wrong region code '%s'", value)
+	}
+	r.Code = value
+	return nil
+}
+
+func TestConfigText(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"LOG_LEVEL":       "error",
+			"WRONG_LOG_LEVEL": "trace",
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		variable *Variable
+		err      error
+		value    logLevel
+	}{
+		{"basic test case", &Variable{Name: "LOG_LEVEL"}, nil, 2},
+		{"wrong value", &Variable{Name: "WRONG_LOG_LEVEL"}, NewParseErrors(&TypeError{Name: "WRONG_LOG_LEVEL", Value: "trace", Type: "gocfg.logLevel", Err: errors.New("unknown log level 'trace'")}), 0},
+		{"string default value", &Variable{Name: "AUDIT_LOG_LEVEL", Default: "info"}, nil, 1},
+		{"typed default value", &Variable{Name: "AUDIT_LOG_LEVEL", Default: logLevel(2)}, nil, 2},
+		{"wrong string default value", &Variable{Name: "AUDIT_LOG_LEVEL", Default: "trace"}, NewParseErrors(&DefaultTypeError{Name: "AUDIT_LOG_LEVEL", Type: "gocfg.logLevel", Err: errors.New("unknown log level 'trace'")}), 0},
+		{"wrong default value type", &Variable{Name: "AUDIT_LOG_LEVEL", Default: 1}, NewParseErrors(&DefaultTypeError{Name: "AUDIT_LOG_LEVEL", Type: "gocfg.logLevel"}), 0},
+		{"validation", &Variable{Name: "LOG_LEVEL", ValidationFunc: func(value interface{}) error {
+			if value.(logLevel) > 1 {
+				return errors.New("log level is too high")
+			}
+			return nil
+		}}, NewParseErrors(&ValidationError{Name: "LOG_LEVEL", Value: "error", Err: errors.New("log level is too high")}), 2},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(env)
+		var value logLevel
+		cfg.SetText(&value, tc.variable)
+		err := cfg.Parse()
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s, Error: '%+v'", tc.name, err))
+		assert.Equal(t, value, tc.value, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestConfigVar(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"REGION":       "eu",
+			"WRONG_REGION": "europe",
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		variable *Variable
+		err      error
+		value    region
+	}{
+		{"basic test case", &Variable{Name: "REGION"}, nil, region{"eu"}},
+		{"wrong value", &Variable{Name: "WRONG_REGION"}, NewParseErrors(&TypeError{Name: "WRONG_REGION", Value: "europe", Type: "gocfg.region", Err: errors.New("wrong region code 'europe'")}), region{}},
+		{"string default value", &Variable{Name: "BACKUP_REGION", Default: "us"}, nil, region{"us"}},
+		{"typed default value", &Variable{Name: "BACKUP_REGION", Default: region{"ap"}}, nil, region{"ap"}},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(env)
+		var value region
+		cfg.SetVar(&value, tc.variable)
+		err := cfg.Parse()
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s, Error: '%+v'", tc.name, err))
+		assert.Equal(t, value, tc.value, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestConfigURL(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"API_URL":       "https://api.example.com:8443/v1",
+			"WRONG_API_URL": "api.example.com",
+		},
+	}
+	apiURL, _ := url.Parse("https://api.example.com:8443/v1")
+	defaultURL, _ := url.Parse("http://localhost:8080")
+
+	testcases := []struct {
+		name     string
+		variable *Variable
+		err      error
+		value    url.URL
+	}{
+		{"basic test case", &Variable{Name: "API_URL"}, nil, *apiURL},
+		{"wrong value", &Variable{Name: "WRONG_API_URL"}, NewParseErrors(&TypeError{Name: "WRONG_API_URL", Value: "api.example.com", Type: "url.URL", Err: errors.New("url scheme is missing")}), url.URL{}},
+		{"string default value", &Variable{Name: "AUTH_URL", Default: "http://localhost:8080"}, nil, *defaultURL},
+		{"typed default value", &Variable{Name: "AUTH_URL", Default: defaultURL}, nil, *defaultURL},
+		{"wrong default value type", &Variable{Name: "AUTH_URL", Default: 1}, NewParseErrors(&DefaultTypeError{Name: "AUTH_URL", Type: "url.URL"}), url.URL{}},
+		{"validation", &Variable{Name: "API_URL", ValidationFunc: ValidateURLScheme("http")}, NewParseErrors(&ValidationError{Name: "API_URL", Value: "https://api.example.com:8443/v1", Err: errors.New("value 'https://api.example.com:8443/v1' scheme is not one of [http]")}), *apiURL},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(env)
+		var value url.URL
+		cfg.SetURL(&value, tc.variable)
+		err := cfg.Parse()
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s, Error: '%+v'", tc.name, err))
+		assert.Equal(t, value, tc.value, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestConfigIP(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"BIND_ADDR":       "10.0.0.1",
+			"BIND_ADDR6":      "::1",
+			"WRONG_BIND_ADDR": "10.0.0.256",
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		variable *Variable
+		err      error
+		value    net.IP
+	}{
+		{"basic test case", &Variable{Name: "BIND_ADDR"}, nil, net.ParseIP("10.0.0.1")},
+		{"ipv6 value", &Variable{Name: "BIND_ADDR6"}, nil, net.ParseIP("::1")},
+		{"wrong value", &Variable{Name: "WRONG_BIND_ADDR"}, NewParseErrors(&TypeError{Name: "WRONG_BIND_ADDR", Value: "10.0.0.256", Type: "net.IP", Err: errors.New("invalid ip address")}), nil},
+		{"string default value", &Variable{Name: "ADMIN_ADDR", Default: "127.0.0.1"}, nil, net.ParseIP("127.0.0.1")},
+		{"typed default value", &Variable{Name: "ADMIN_ADDR", Default: net.ParseIP("127.0.0.1")}, nil, net.ParseIP("127.0.0.1")},
+		{"wrong string default value", &Variable{Name: "ADMIN_ADDR", Default: "localhost"}, NewParseErrors(&DefaultTypeError{Name: "ADMIN_ADDR", Type: "net.IP", Err: errors.New("invalid ip address")}), nil},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(env)
+		var value net.IP
+		cfg.SetIP(&value, tc.variable)
+		err := cfg.Parse()
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s, Error: '%+v'", tc.name, err))
+		assert.Equal(t, value, tc.value, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestConfigIPNet(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"TRUSTED_NET":       "10.0.0.1/8",
+			"WRONG_TRUSTED_NET": "10.0.0.0",
+		},
+	}
+	_, trusted, _ := net.ParseCIDR("10.0.0.0/8")
+	_, local, _ := net.ParseCIDR("127.0.0.0/8")
+
+	testcases := []struct {
+		name     string
+		variable *Variable
+		err      error
+		value    net.IPNet
+	}{
+		{"basic test case", &Variable{Name: "TRUSTED_NET"}, nil, *trusted},
+		{"wrong value", &Variable{Name: "WRONG_TRUSTED_NET"}, NewParseErrors(&TypeError{Name: "WRONG_TRUSTED_NET", Value: "10.0.0.0", Type: "net.IPNet", Err: &net.ParseError{Type: "CIDR address", Text: "10.0.0.0"}}), net.IPNet{}},
+		{"string default value", &Variable{Name: "LOCAL_NET", Default: "127.0.0.0/8"}, nil, *local},
+		{"typed default value", &Variable{Name: "LOCAL_NET", Default: *local}, nil, *local},
+		{"pointer default value", &Variable{Name: "LOCAL_NET", Default: local}, nil, *local},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(env)
+		var value net.IPNet
+		cfg.SetIPNet(&value, tc.variable)
+		err := cfg.Parse()
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s, Error: '%+v'", tc.name, err))
+		assert.Equal(t, value, tc.value, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestConfigNetworkValidators(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"API_URL":     "https://api.example.com:8443/v1",
+			"BIND_ADDR":   "10.0.0.1",
+			"TRUSTED_NET": "10.0.0.0/8",
+		},
+	}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	var apiURL url.URL
+	var bindAddr net.IP
+	var trustedNet net.IPNet
+	cfg.SetURL(&apiURL, &Variable{Name: "API_URL", Validators: []func(value interface{}) error{
+		ValidateURLScheme("https"),
+		ValidateHostPort(),
+		ValidatePortRange(8000, 9000),
+	}})
+	cfg.SetIP(&bindAddr, &Variable{Name: "BIND_ADDR", ValidationFunc: ValidateIP()})
+	cfg.SetIPNet(&trustedNet, &Variable{Name: "TRUSTED_NET", ValidationFunc: ValidateCIDR()})
+	assert.Nil(t, cfg.Parse())
+
+	var port int
+	cfg = New()
+	cfg.SetEnvLookuper(env)
+	cfg.SetInt(&port, &Variable{Name: "PORT", Default: 8080, Validators: []func(value interface{}) error{ValidateIP(), ValidateCIDR(), ValidateHostPort()}})
+	// wrong value types fail validation instead of panicking
+	assert.Equal(t, NewParseErrors(
+		&ValidationError{Name: "PORT", Value: "8080", Err: errors.New("value '8080' is not an IP address")},
+		&ValidationError{Name: "PORT", Value: "8080", Err: errors.New("value '8080' is not a CIDR")},
+		&ValidationError{Name: "PORT", Value: "8080", Err: errors.New("value '8080' is not a host:port pair")},
+	), cfg.Parse())
+}
+
+func TestConfigStrictMode(t *testing.T) {
+	testcases := []struct {
+		name     string
+		variable *Variable
+		err      error
+	}{
+		{"valid default value", &Variable{Name: "BATCH_SIZE", Default: 500, ValidationFunc: ValidateMax(1000)}, nil},
+		{"no default value", &Variable{Name: "BATCH_SIZE", Required: true}, nil},
+		{"wrong default value type", &Variable{Name: "BATCH_SIZE", Default: "500"}, fmt.Errorf("gocfg: %w", &DefaultTypeError{Name: "BATCH_SIZE", Type: "int"})},
+		{"invalid default value", &Variable{Name: "BATCH_SIZE", Default: 5000, ValidationFunc: ValidateMax(1000)}, fmt.Errorf("gocfg: %w", &ValidationError{Name: "BATCH_SIZE", Value: "5000", Err: errors.New("value '5000' is greater than '1000'")})},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetStrictMode(true)
+		var value int
+		if tc.err == nil {
+			assert.NotPanics(t, func() { cfg.SetInt(&value, tc.variable) }, fmt.Sprintf("Test case: %s", tc.name))
+			continue
+		}
+		assert.PanicsWithError(t, tc.err.Error(), func() { cfg.SetInt(&value, tc.variable) }, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
@@ -0,0 +1,357 @@
+package gocfg
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DotEnvLookuper implements EnvLookuper interface. It lookups variables
+// defined in .env files. It's safe for concurrent use.
+type DotEnvLookuper struct {
+	// mu guards vars replaced by Reload
+	mu    sync.RWMutex
+	vars  map[string]string
+	paths []string
+}
+
+// NewDotEnvLookuper reads and parses .env files. Variables defined in later
+// files override variables of earlier ones.
+//
+// Supported syntax:
+// - KEY=value, optionally prefixed with "export"
+// - comments on separate lines and after unquoted values
+// - single-quoted values, which are taken literally
+// - double-quoted values with \n, \r, \t, \", \\ and \$ escapes
+// - multiline quoted values
+// - ${VAR}, ${VAR:-default} and $VAR references
+//
+// References in unquoted and double-quoted values are resolved with
+// variables defined above and then with the process environment.
+//
+// Syntax errors are reported as "file:line: message".
+func NewDotEnvLookuper(paths ...string) (*DotEnvLookuper, error) {
+	l := &DotEnvLookuper{vars: map[string]string{}, paths: paths}
+	for _, path := range paths {
+		if err := l.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Reload implements Reloader interface. It re-reads .env files and returns
+// the lookuper of new variables and func which makes them current. It
+// returns error if any file can't be read or parsed.
+func (l *DotEnvLookuper) Reload() (EnvLookuper, func(), error) {
+	n, err := NewDotEnvLookuper(l.paths...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return n, func() {
+		l.mu.Lock()
+		l.vars = n.vars
+		l.mu.Unlock()
+	}, nil
+}
+
+// readFile parses .env file and adds it's variables to the lookuper.
+func (l *DotEnvLookuper) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return parseDotEnv(f, path, l.vars)
+}
+
+// LookupEnv lookups variable defined in .env files.
+func (l *DotEnvLookuper) LookupEnv(key string) (string, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	v, ok := l.vars[key]
+	return v, ok
+}
+
+// ParseDotEnv parses .env data and returns defined variables. The name is
+// used in error messages.
+func ParseDotEnv(r io.Reader, name string) (map[string]string, error) {
+	vars := map[string]string{}
+	if err := parseDotEnv(r, name, vars); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// parseDotEnv parses .env data and adds variables to vars.
+func parseDotEnv(r io.Reader, name string, vars map[string]string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	p := &dotEnvParser{name: name, data: string(data), line: 1, vars: vars}
+	return p.parse()
+}
+
+// dotEnvParser parses .env data.
+type dotEnvParser struct {
+	name string
+	data string
+	pos  int
+	line int
+	vars map[string]string
+}
+
+// errorf returns error which points to the line of .env file.
+func (p *dotEnvParser) errorf(line int, format string, args ...interface{}) error {
+	return fmt.Errorf("%s:%d: %s", p.name, line, fmt.Sprintf(format, args...))
+}
+
+func (p *dotEnvParser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *dotEnvParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.data[p.pos]
+}
+
+// next returns current character and moves to the next one.
+func (p *dotEnvParser) next() byte {
+	c := p.data[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+// skipInlineSpace skips spaces and tabs.
+func (p *dotEnvParser) skipInlineSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\r') {
+		p.next()
+	}
+}
+
+// skipLine skips everything till the end of line.
+func (p *dotEnvParser) skipLine() {
+	for !p.eof() && p.next() != '\n' {
+	}
+}
+
+// skipBlank skips empty lines and comments.
+func (p *dotEnvParser) skipBlank() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\r', '\n':
+			p.next()
+		case '#':
+			p.skipLine()
+		default:
+			return
+		}
+	}
+}
+
+func (p *dotEnvParser) parse() error {
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil
+		}
+		if err := p.parseVariable(); err != nil {
+			return err
+		}
+	}
+}
+
+// parseVariable parses single KEY=value definition.
+func (p *dotEnvParser) parseVariable() error {
+	line := p.line
+	key := p.readKey()
+	if key == "export" && (p.peek() == ' ' || p.peek() == '\t') {
+		p.skipInlineSpace()
+		key = p.readKey()
+	}
+	if key == "" {
+		return p.errorf(line, "invalid variable name")
+	}
+	p.skipInlineSpace()
+	if p.peek() != '=' {
+		return p.errorf(line, "expected '=' after variable name '%s'", key)
+	}
+	p.next()
+	p.skipInlineSpace()
+	var value string
+	var err error
+	switch p.peek() {
+	case '\'':
+		value, err = p.readSingleQuoted()
+	case '"':
+		value, err = p.readDoubleQuoted()
+	default:
+		value, err = p.readUnquoted()
+	}
+	if err != nil {
+		return err
+	}
+	// only a comment is allowed after the value
+	p.skipInlineSpace()
+	if !p.eof() && p.peek() != '\n' && p.peek() != '#' {
+		return p.errorf(p.line, "unexpected character '%c' after value of '%s'", p.peek(), key)
+	}
+	p.skipLine()
+	p.vars[key] = value
+	return nil
+}
+
+// readKey reads variable name.
+func (p *dotEnvParser) readKey() string {
+	start := p.pos
+	for !p.eof() && isDotEnvKeyChar(p.peek()) {
+		p.next()
+	}
+	return p.data[start:p.pos]
+}
+
+// readSingleQuoted reads value till the closing quote without any
+// interpretation.
+func (p *dotEnvParser) readSingleQuoted() (string, error) {
+	line := p.line
+	p.next()
+	start := p.pos
+	for !p.eof() {
+		if p.peek() == '\'' {
+			v := p.data[start:p.pos]
+			p.next()
+			return v, nil
+		}
+		p.next()
+	}
+	return "", p.errorf(line, "unterminated single-quoted value")
+}
+
+// readDoubleQuoted reads value till the closing quote, interprets escape
+// sequences and resolves references.
+func (p *dotEnvParser) readDoubleQuoted() (string, error) {
+	line := p.line
+	p.next()
+	var b strings.Builder
+	for !p.eof() {
+		c := p.next()
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", p.errorf(line, "unterminated double-quoted value")
+			}
+			e := p.next()
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(e)
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(e)
+			}
+		case '$':
+			v, err := p.readReference()
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf(line, "unterminated double-quoted value")
+}
+
+// readUnquoted reads value till the end of line or comment and resolves
+// references. Leading and trailing spaces are trimmed.
+func (p *dotEnvParser) readUnquoted() (string, error) {
+	var b strings.Builder
+	for !p.eof() && p.peek() != '\n' {
+		c := p.peek()
+		// comment starts with '#' after a space
+		if c == '#' && (b.Len() == 0 || isDotEnvSpace(p.data[p.pos-1])) {
+			break
+		}
+		p.next()
+		if c == '$' {
+			v, err := p.readReference()
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// readReference resolves ${VAR}, ${VAR:-default} or $VAR reference. The
+// leading '$' has been already read.
+func (p *dotEnvParser) readReference() (string, error) {
+	line := p.line
+	if p.peek() != '{' {
+		start := p.pos
+		for !p.eof() && isDotEnvNameChar(p.peek()) {
+			p.next()
+		}
+		name := p.data[start:p.pos]
+		if name == "" {
+			return "$", nil
+		}
+		return p.resolve(name), nil
+	}
+	p.next()
+	end := strings.IndexByte(p.data[p.pos:], '}')
+	if end < 0 || strings.Contains(p.data[p.pos:p.pos+end], "\n") {
+		return "", p.errorf(line, "unterminated variable reference")
+	}
+	ref := p.data[p.pos : p.pos+end]
+	p.pos += end + 1
+	name, def, hasDefault := strings.Cut(ref, ":-")
+	if name == "" || strings.IndexFunc(name, func(r rune) bool { return r > 127 || !isDotEnvNameChar(byte(r)) }) >= 0 {
+		return "", p.errorf(line, "invalid variable reference '${%s}'", ref)
+	}
+	v := p.resolve(name)
+	if v == "" && hasDefault {
+		return def, nil
+	}
+	return v, nil
+}
+
+// resolve returns value of the variable defined above or of the process
+// environment variable.
+func (p *dotEnvParser) resolve(name string) string {
+	if v, ok := p.vars[name]; ok {
+		return v
+	}
+	return os.Getenv(name)
+}
+
+func isDotEnvKeyChar(c byte) bool {
+	return c == '.' || c == '-' || isDotEnvNameChar(c)
+}
+
+// isDotEnvNameChar returns true if c is allowed in references.
+func isDotEnvNameChar(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func isDotEnvSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
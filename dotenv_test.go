@@ -0,0 +1,81 @@
+package gocfg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	data := `# database settings
+DB_HOST=localhost
+export DB_PORT = 5432 # inline comment
+DB_URL=postgres://${DB_HOST}:$DB_PORT/app
+DB_PASSWORD='pa$$word # not a comment'
+GREETING="Hello,\n\"world\" \$HOME"
+CERT="-----BEGIN-----
+line
+-----END-----"
+EMPTY=
+URL_WITH_HASH=http://example.com/#anchor
+LOG_LEVEL=${UNDEFINED_LOG_LEVEL:-info}
+`
+	vars, err := ParseDotEnv(strings.NewReader(data), ".env")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"DB_HOST":       "localhost",
+		"DB_PORT":       "5432",
+		"DB_URL":        "postgres://localhost:5432/app",
+		"DB_PASSWORD":   "pa$$word # not a comment",
+		"GREETING":      "Hello,\n\"world\" $HOME",
+		"CERT":          "-----BEGIN-----\nline\n-----END-----",
+		"EMPTY":         "",
+		"URL_WITH_HASH": "http://example.com/#anchor",
+		"LOG_LEVEL":     "info",
+	}, vars)
+}
+
+func TestParseDotEnvErrors(t *testing.T) {
+	testcases := []struct {
+		name string
+		data string
+		err  error
+	}{
+		{"missing equal sign", "A=1\nB 2\n", errors.New(".env:2: expected '=' after variable name 'B'")},
+		{"invalid name", "A=1\n\n=2\n", errors.New(".env:3: invalid variable name")},
+		{"unterminated double quote", "A=1\nB=\"multi\nline\n", errors.New(".env:2: unterminated double-quoted value")},
+		{"unterminated single quote", "A='1\n", errors.New(".env:1: unterminated single-quoted value")},
+		{"text after quoted value", "A='1' 2\n", errors.New(".env:1: unexpected character '2' after value of 'A'")},
+		{"unterminated reference", "A=${B\n", errors.New(".env:1: unterminated variable reference")},
+		{"invalid reference", "A=${B C}\n", errors.New(".env:1: invalid variable reference '${B C}'")},
+	}
+	for _, tc := range testcases {
+		_, err := ParseDotEnv(strings.NewReader(tc.data), ".env")
+		assert.Equal(t, tc.err, err, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestDotEnvLookuper(t *testing.T) {
+	dir := t.TempDir()
+	env := filepath.Join(dir, ".env")
+	local := filepath.Join(dir, ".env.local")
+	assert.Nil(t, os.WriteFile(env, []byte("API_URL=https://api.example.com\nAPI_VERSION=v1\n"), 0o600))
+	assert.Nil(t, os.WriteFile(local, []byte("API_VERSION=v2\nAPI=${API_URL}/${API_VERSION}\n"), 0o600))
+
+	l, err := NewDotEnvLookuper(env, local)
+	assert.Nil(t, err)
+	cfg := New()
+	cfg.SetEnvLookuper(l)
+	var api string
+	cfg.SetString(&api, &Variable{Name: "API", Required: true})
+	assert.Nil(t, cfg.Parse())
+	assert.Equal(t, "https://api.example.com/v2", api)
+
+	_, err = NewDotEnvLookuper(filepath.Join(dir, ".env.missing"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,101 @@
+package gocfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// DumpFormat is a format of Dump output.
+type DumpFormat string
+
+// Supported Dump formats.
+const (
+	// DumpText is a table of variables.
+	DumpText DumpFormat = "text"
+	// DumpJSON is an array of variable objects.
+	DumpJSON DumpFormat = "json"
+	// DumpEnv is a .env file with comments.
+	DumpEnv DumpFormat = "env"
+)
+
+// dumpVariable is the effective state of a variable.
+type dumpVariable struct {
+	Name string `json:"name"`
+	// Source is a name of the source which supplied the value,
+	// SourceDefault or empty string if the variable was not set.
+	Source string `json:"source"`
+	// Raw is the value before conversion. It's empty if the value was not
+	// looked up in a source.
+	Raw string `json:"raw,omitempty"`
+	// Value is the final value formatted like the environment variable
+	// value. JSONValue is the final value of the JSON type of the variable.
+	Value     string      `json:"-"`
+	JSONValue interface{} `json:"value"`
+	Sensitive bool        `json:"sensitive,omitempty"`
+}
+
+// Dump writes the effective configuration: final values of all variables,
+// sources which supplied them and raw values before conversion. Values of
+// sensitive variables are masked.
+func (c *Config) Dump(w io.Writer, format DumpFormat) error {
+	c.mu.RLock()
+	vars := make([]dumpVariable, len(c.variables))
+	for i, v := range c.variables {
+		vars[i] = dumpState(v)
+	}
+	c.mu.RUnlock()
+	switch format {
+	case DumpText:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tSOURCE\tRAW\tVALUE")
+		for _, v := range vars {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", v.Name, v.Source, v.Raw, v.Value)
+		}
+		return tw.Flush()
+	case DumpJSON:
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(vars)
+	case DumpEnv:
+		var b strings.Builder
+		for _, v := range vars {
+			source := v.Source
+			if source == "" {
+				source = "not set"
+			}
+			value := v.Value
+			if !v.Sensitive {
+				value = dotEnvQuote(value)
+			}
+			fmt.Fprintf(&b, "# source: %s\n%s=%s\n", source, v.Name, value)
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	return fmt.Errorf("unsupported dump format '%s'", format)
+}
+
+// dumpState returns the effective state of the variable.
+func dumpState(v *Variable) dumpVariable {
+	p := reflect.ValueOf(v.pointer).Elem()
+	d := dumpVariable{
+		Name:      v.Name,
+		Source:    v.source,
+		Raw:       v.raw,
+		Value:     formatValue(v, p.Interface()),
+		JSONValue: jsonValue(v, v.valueType, p),
+		Sensitive: v.Sensitive,
+	}
+	if v.Sensitive {
+		if d.Raw != "" {
+			d.Raw = RedactedValue
+		}
+		d.Value = RedactedValue
+		d.JSONValue = RedactedValue
+	}
+	return d
+}
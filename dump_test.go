@@ -0,0 +1,96 @@
+package gocfg
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newDumpConfig returns parsed config with variables from different
+// sources.
+func newDumpConfig(t *testing.T) *Config {
+	cfg := New()
+	cfg.SetEnvLookuper(&EnvLookuperMock{vars: map[string]string{
+		"API_URL":     "https://api.example.com",
+		"DB_PASSWORD": "s3cr3t",
+		"TIMEOUT":     "90",
+		"BROKERS":     " a:9092 , b:9092 ",
+	}})
+	var apiURL, region string
+	var password Secret
+	var timeout time.Duration
+	var workers int
+	var brokers []string
+	cfg.SetString(&apiURL, &Variable{Name: "API_URL"})
+	cfg.SetSecret(&password, &Variable{Name: "DB_PASSWORD"})
+	cfg.SetDuration(&timeout, &Variable{Name: "TIMEOUT"})
+	cfg.SetInt(&workers, &Variable{Name: "WORKERS", Default: 4})
+	cfg.SetStringSlice(&brokers, &Variable{Name: "BROKERS", TrimSpace: true})
+	cfg.SetString(&region, &Variable{Name: "REGION"})
+	assert.Nil(t, cfg.Parse())
+	return cfg
+}
+
+func TestConfigDumpText(t *testing.T) {
+	cfg := newDumpConfig(t)
+	var b bytes.Buffer
+	assert.Nil(t, cfg.Dump(&b, DumpText))
+	expected := []string{
+		"NAME         SOURCE   RAW                      VALUE",
+		"API_URL      env      https://api.example.com  https://api.example.com",
+		"DB_PASSWORD  env      ******                   ******",
+		"TIMEOUT      env      90                       1m30s",
+		"WORKERS      default                           4",
+		"BROKERS      env       a:9092 , b:9092         a:9092,b:9092",
+		"REGION                                         ",
+		"",
+	}
+	assert.Equal(t, strings.Join(expected, "\n"), b.String())
+}
+
+func TestConfigDumpJSON(t *testing.T) {
+	cfg := newDumpConfig(t)
+	var b bytes.Buffer
+	assert.Nil(t, cfg.Dump(&b, DumpJSON))
+	expected := `[
+		{"name": "API_URL", "source": "env", "raw": "https://api.example.com", "value": "https://api.example.com"},
+		{"name": "DB_PASSWORD", "source": "env", "raw": "******", "value": "******", "sensitive": true},
+		{"name": "TIMEOUT", "source": "env", "raw": "90", "value": "1m30s"},
+		{"name": "WORKERS", "source": "default", "value": 4},
+		{"name": "BROKERS", "source": "env", "raw": " a:9092 , b:9092 ", "value": ["a:9092", "b:9092"]},
+		{"name": "REGION", "source": "", "value": ""}
+	]`
+	assert.JSONEq(t, expected, b.String())
+}
+
+func TestConfigDumpEnv(t *testing.T) {
+	cfg := newDumpConfig(t)
+	var b bytes.Buffer
+	assert.Nil(t, cfg.Dump(&b, DumpEnv))
+	expected := []string{
+		"# source: env",
+		"API_URL=https://api.example.com",
+		"# source: env",
+		"DB_PASSWORD=******",
+		"# source: env",
+		"TIMEOUT=1m30s",
+		"# source: default",
+		"WORKERS=4",
+		"# source: env",
+		"BROKERS=a:9092,b:9092",
+		"# source: not set",
+		"REGION=",
+		"",
+	}
+	assert.Equal(t, strings.Join(expected, "\n"), b.String())
+}
+
+func TestConfigDumpUnsupportedFormat(t *testing.T) {
+	cfg := newDumpConfig(t)
+	var b bytes.Buffer
+	assert.Equal(t, errors.New("unsupported dump format 'xml'"), cfg.Dump(&b, "xml"))
+}
@@ -0,0 +1,174 @@
+package gocfg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Kinds of parse errors. Check the kind with errors.Is or get errors of the
+// kind with ParseErrors.Filter.
+var (
+	// ErrMissing is the kind of MissingError.
+	ErrMissing = errors.New("variable is missing")
+	// ErrType is the kind of TypeError.
+	ErrType = errors.New("variable has a wrong value type")
+	// ErrValidation is the kind of ValidationError.
+	ErrValidation = errors.New("variable validation failed")
+	// ErrDefaultType is the kind of DefaultTypeError.
+	ErrDefaultType = errors.New("variable has a wrong default value")
+	// ErrRule is the kind of RuleError.
+	ErrRule = errors.New("rule is violated")
+)
+
+// MissingError is returned if required variable is not defined.
+type MissingError struct {
+	Name string
+}
+
+// Error implements Error method of error interface.
+func (e *MissingError) Error() string {
+	return fmt.Sprintf("'%s' variable is missing", e.Name)
+}
+
+// Is returns true if target is ErrMissing.
+func (e *MissingError) Is(target error) bool {
+	return target == ErrMissing
+}
+
+// TypeError is returned if variable value can't be converted to the
+// variable type. Value is the raw value, or the malformed SLICE element or
+// MAP pair, it's RedactedValue if the variable is sensitive. Type is the go
+// type of the value.
+type TypeError struct {
+	Name  string
+	Value string
+	Type  string
+	Err   error
+}
+
+// Error implements Error method of error interface.
+func (e *TypeError) Error() string {
+	var me *mapError
+	switch {
+	case errors.Is(e.Err, strconv.ErrRange):
+		return fmt.Sprintf("variable '%s' value is out of %s range", e.Name, e.Type)
+	case errors.As(e.Err, &me):
+		return fmt.Sprintf("variable '%s' has %s", e.Name, me.msg)
+	}
+	return fmt.Sprintf("variable '%s' has a wrong value type", e.Name)
+}
+
+// Unwrap returns the cause of error.
+func (e *TypeError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is ErrType.
+func (e *TypeError) Is(target error) bool {
+	return target == ErrType
+}
+
+// mapError describes malformed MAP value.
+type mapError struct {
+	msg string
+}
+
+func (e *mapError) Error() string {
+	return e.msg
+}
+
+// ValidationError is returned if variable value is not valid. Value is the
+// raw value, it's RedactedValue if the variable is sensitive. The message is
+// the message of the validation func error.
+type ValidationError struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+// Error implements Error method of error interface.
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the validation func error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// DefaultTypeError is returned if variable default value has a wrong type
+// or can't be converted to the variable type. Type is the go type of the
+// variable, Err is the conversion error of string defaults.
+type DefaultTypeError struct {
+	Name string
+	Type string
+	Err  error
+}
+
+// Error implements Error method of error interface.
+func (e *DefaultTypeError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("variable '%s' has a wrong default value", e.Name)
+	}
+	return fmt.Sprintf("variable '%s' has a wrong default value type", e.Name)
+}
+
+// Unwrap returns the cause of error.
+func (e *DefaultTypeError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is ErrDefaultType.
+func (e *DefaultTypeError) Is(target error) bool {
+	return target == ErrDefaultType
+}
+
+// RuleError is returned if a rule of several variables is violated.
+type RuleError struct {
+	Names []string
+	Err   error
+	// custom rule errors are prefixed with the names of variables
+	custom bool
+}
+
+// Error implements Error method of error interface.
+func (e *RuleError) Error() string {
+	if e.custom {
+		return fmt.Sprintf("variables %s: %s", quoteNames(e.Names), e.Err)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the rule error.
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is ErrRule.
+func (e *RuleError) Is(target error) bool {
+	return target == ErrRule
+}
+
+// newValidationError returns ValidationError for the validation failure or
+// ValidationErrors of ValidationError for multiple failures. Errors of
+// sensitive variables are replaced, since validation funcs could print the
+// value.
+func newValidationError(setting *Variable, err error, raw string) error {
+	if errs, ok := err.(ValidationErrors); ok {
+		wrapped := make(ValidationErrors, len(errs))
+		for i, e := range errs {
+			wrapped[i] = newValidationError(setting, e, raw)
+		}
+		return wrapped
+	}
+	if setting.Sensitive {
+		err = redactError(err, ErrValidation, fmt.Sprintf("variable '%s' value '%s' is not valid", setting.Name, RedactedValue))
+	}
+	return &ValidationError{Name: setting.Name, Value: displayValue(setting, raw), Err: err}
+}
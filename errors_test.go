@@ -0,0 +1,54 @@
+package gocfg
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseErrorsKinds(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"BATCH_SIZE":   "many",
+			"API_URL":      "http://api.example.com",
+			"READ_TIMEOUT": "s3cr3t",
+		},
+	}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	var batchSize, workers int
+	var apiURL, region string
+	var timeout time.Duration
+	cfg.SetInt(&batchSize, &Variable{Name: "BATCH_SIZE"})
+	cfg.SetInt(&workers, &Variable{Name: "WORKERS", Default: "4"})
+	cfg.SetString(&apiURL, &Variable{Name: "API_URL", ValidationFunc: ValidateStringHasPrefix("https")})
+	cfg.SetString(&region, &Variable{Name: "REGION", Required: true})
+	cfg.SetDuration(&timeout, &Variable{Name: "READ_TIMEOUT", Sensitive: true})
+	cfg.MutuallyExclusive("API_URL", "BATCH_SIZE")
+	err := cfg.Parse()
+
+	var pe *ParseErrors
+	assert.True(t, errors.As(err, &pe))
+	assert.Len(t, pe.Errors(), 5)
+	assert.True(t, errors.Is(err, ErrMissing))
+	assert.True(t, errors.Is(err, strconv.ErrSyntax))
+	assert.False(t, errors.Is(err, ErrRule))
+
+	assert.Equal(t, []error{&TypeError{Name: "BATCH_SIZE", Value: "many", Type: "int", Err: strconv.ErrSyntax}, pe.Errors()[4]}, pe.Filter(ErrType))
+	assert.Equal(t, []error{&DefaultTypeError{Name: "WORKERS", Type: "int"}}, pe.Filter(ErrDefaultType))
+	assert.Equal(t, []error{&MissingError{Name: "REGION"}}, pe.Filter(ErrMissing))
+
+	var ve *ValidationError
+	assert.True(t, errors.As(err, &ve))
+	assert.Equal(t, "API_URL", ve.Name)
+	assert.Equal(t, "http://api.example.com", ve.Value)
+
+	// causes of sensitive variables errors are replaced
+	var te *TypeError
+	assert.True(t, errors.As(pe.Errors()[4], &te))
+	assert.Equal(t, RedactedValue, te.Value)
+	assert.Equal(t, &redactedError{msg: "value '******' is not time.Duration", kind: ErrType}, te.Err)
+}
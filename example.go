@@ -0,0 +1,173 @@
+package gocfg
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RequiredMarker marks required variables without default values in
+// generated examples.
+const RequiredMarker = "REQUIRED"
+
+// KubernetesSecretName is the name of the Kubernetes secret referenced by
+// sensitive variables in generated Kubernetes env lists. It's a stub which
+// should be replaced with the real secret name.
+const KubernetesSecretName = "config-secrets"
+
+// exampleComments returns comment lines of the variable in generated
+// examples: description, required marker and validation summary. Multi-line
+// comments are split into lines.
+func exampleComments(v *Variable) []string {
+	var comments []string
+	if v.Description != "" {
+		comments = append(comments, v.Description)
+	}
+	switch {
+	case v.Required && v.Default == nil:
+		comments = append(comments, RequiredMarker+": no default value")
+	case v.Required:
+		comments = append(comments, "required")
+	}
+	if v.Sensitive {
+		comments = append(comments, "sensitive")
+	}
+	if s := validationSummary(v); len(s) > 0 {
+		comments = append(comments, "validation: "+strings.Join(s, ", "))
+	}
+	var lines []string
+	for _, c := range comments {
+		lines = append(lines, strings.Split(strings.ReplaceAll(c, "\r\n", "\n"), "\n")...)
+	}
+	return lines
+}
+
+// exampleDefault returns the default value formatted like the environment
+// variable value. It's empty if the variable is sensitive.
+func exampleDefault(v *Variable) string {
+	if v.Default == nil || v.Sensitive {
+		return ""
+	}
+	return formatValue(v, v.Default)
+}
+
+// ExportEnvExample writes .env.example file with all defined variables.
+// Variables with default values are commented out, so defaults are used
+// until they are uncommented. Required variables without default values are
+// marked with RequiredMarker.
+func (c *Config) ExportEnvExample(w io.Writer) error {
+	var b strings.Builder
+	for i, v := range c.variables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for _, comment := range exampleComments(v) {
+			b.WriteString("# " + comment + "\n")
+		}
+		if v.Default != nil {
+			b.WriteString("# ")
+		}
+		b.WriteString(v.Name + "=" + dotEnvQuote(exampleDefault(v)) + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotEnvPlain matches values which don't need quoting in .env files.
+var dotEnvPlain = regexp.MustCompile(`^[A-Za-z0-9_./:,=@+-]*$`)
+
+// dotEnvQuote returns the value double-quoted if it has spaces or special
+// characters.
+func dotEnvQuote(s string) string {
+	if dotEnvPlain.MatchString(s) {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// ExportKubernetesEnv writes Kubernetes container `env:` list with all
+// defined variables. Values are default values, required variables without
+// default values are marked with RequiredMarker and have empty values.
+// Sensitive variables are taken from the KubernetesSecretName secret by
+// `valueFrom.secretKeyRef` with the variable name as the key.
+func (c *Config) ExportKubernetesEnv(w io.Writer) error {
+	list := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, v := range c.variables {
+		value := []*yaml.Node{yamlString("value"), yamlQuoted(exampleDefault(v))}
+		if v.Sensitive {
+			ref := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+				yamlString("name"), yamlString(KubernetesSecretName),
+				yamlString("key"), yamlString(v.Name),
+			}}
+			value = []*yaml.Node{yamlString("valueFrom"), {Kind: yaml.MappingNode, Content: []*yaml.Node{
+				yamlString("secretKeyRef"), ref,
+			}}}
+		}
+		item := &yaml.Node{
+			Kind:        yaml.MappingNode,
+			HeadComment: yamlComment(v),
+			Content:     append([]*yaml.Node{yamlString("name"), yamlString(v.Name)}, value...),
+		}
+		list.Content = append(list.Content, item)
+	}
+	env := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{yamlString("env"), list}}
+	return encodeYAML(w, env)
+}
+
+// ExportComposeEnv writes docker-compose service `environment:` block with
+// all defined variables. Values are default values, required variables
+// without default values are marked with RequiredMarker and must be set in
+// the shell environment, e.g. ${API_URL:?API_URL is required}. Values of
+// sensitive variables are taken from the shell environment as well.
+func (c *Config) ExportComposeEnv(w io.Writer) error {
+	env := &yaml.Node{Kind: yaml.MappingNode}
+	for _, v := range c.variables {
+		value := exampleDefault(v)
+		switch {
+		case v.Required && v.Default == nil:
+			value = fmt.Sprintf("${%s:?%s is required}", v.Name, v.Name)
+		case v.Sensitive:
+			value = fmt.Sprintf("${%s}", v.Name)
+		default:
+			// compose interpolates $ in values
+			value = strings.ReplaceAll(value, "$", "$$")
+		}
+		key := yamlString(v.Name)
+		key.HeadComment = yamlComment(v)
+		env.Content = append(env.Content, key, yamlQuoted(value))
+	}
+	root := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{yamlString("environment"), env}}
+	return encodeYAML(w, root)
+}
+
+// yamlComment returns YAML comment of the variable.
+func yamlComment(v *Variable) string {
+	return strings.Join(exampleComments(v), "\n")
+}
+
+// yamlString returns YAML scalar node of the string.
+func yamlString(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}
+
+// yamlQuoted returns double-quoted YAML scalar node of the string, so values
+// like "true" or "8080" are kept strings.
+func yamlQuoted(s string) *yaml.Node {
+	n := yamlString(s)
+	n.Style = yaml.DoubleQuotedStyle
+	return n
+}
+
+// encodeYAML writes YAML document of the node.
+func encodeYAML(w io.Writer, n *yaml.Node) error {
+	e := yaml.NewEncoder(w)
+	e.SetIndent(2)
+	if err := e.Encode(n); err != nil {
+		return err
+	}
+	return e.Close()
+}
@@ -0,0 +1,102 @@
+package gocfg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigExportEnvExample(t *testing.T) {
+	cfg := newUsageConfig(nil)
+	var greeting string
+	cfg.SetString(&greeting, &Variable{Name: "GREETING", Default: `say "hi" to $USER`, Description: "greeting line one\nline two"})
+	var b bytes.Buffer
+	assert.Nil(t, cfg.ExportEnvExample(&b))
+	expected := []string{
+		"# API endpoint",
+		"# REQUIRED: no default value",
+		"# validation: starts with 'https', not (contains 'localhost')",
+		"API_URL=",
+		"",
+		"# sensitive",
+		"# DB_PASSWORD=",
+		"",
+		"# number of workers",
+		"# validation: between 1 and 16, any of (multiple of 2; one of [1])",
+		"# WORKERS=4",
+		"",
+		"# validation: each host:port",
+		"# KAFKA_BROKERS=a:9092,b:9092",
+		"",
+		"# validation: custom",
+		"MODE=",
+		"",
+		"# greeting line one",
+		"# line two",
+		`# GREETING="say \"hi\" to \$USER"`,
+		"",
+	}
+	assert.Equal(t, strings.Join(expected, "\n"), b.String())
+
+	// the example is a valid .env file
+	_, err := ParseDotEnv(&b, ".env.example")
+	assert.Nil(t, err)
+}
+
+func TestConfigExportKubernetesEnv(t *testing.T) {
+	cfg := newUsageConfig(nil)
+	var b bytes.Buffer
+	assert.Nil(t, cfg.ExportKubernetesEnv(&b))
+	expected := []string{
+		"env:",
+		"  # API endpoint",
+		"  # REQUIRED: no default value",
+		"  # validation: starts with 'https', not (contains 'localhost')",
+		"  - name: API_URL",
+		`    value: ""`,
+		"  # sensitive",
+		"  - name: DB_PASSWORD",
+		"    valueFrom:",
+		"      secretKeyRef:",
+		"        name: config-secrets",
+		"        key: DB_PASSWORD",
+		"  # number of workers",
+		"  # validation: between 1 and 16, any of (multiple of 2; one of [1])",
+		"  - name: WORKERS",
+		`    value: "4"`,
+		"  # validation: each host:port",
+		"  - name: KAFKA_BROKERS",
+		`    value: "a:9092,b:9092"`,
+		"  # validation: custom",
+		"  - name: MODE",
+		`    value: ""`,
+		"",
+	}
+	assert.Equal(t, strings.Join(expected, "\n"), b.String())
+}
+
+func TestConfigExportComposeEnv(t *testing.T) {
+	cfg := newUsageConfig(nil)
+	var b bytes.Buffer
+	assert.Nil(t, cfg.ExportComposeEnv(&b))
+	expected := []string{
+		"environment:",
+		"  # API endpoint",
+		"  # REQUIRED: no default value",
+		"  # validation: starts with 'https', not (contains 'localhost')",
+		`  API_URL: "${API_URL:?API_URL is required}"`,
+		"  # sensitive",
+		`  DB_PASSWORD: "${DB_PASSWORD}"`,
+		"  # number of workers",
+		"  # validation: between 1 and 16, any of (multiple of 2; one of [1])",
+		`  WORKERS: "4"`,
+		"  # validation: each host:port",
+		`  KAFKA_BROKERS: "a:9092,b:9092"`,
+		"  # validation: custom",
+		`  MODE: ""`,
+		"",
+	}
+	assert.Equal(t, strings.Join(expected, "\n"), b.String())
+}
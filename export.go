@@ -0,0 +1,269 @@
+package gocfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// JSONSchemaDraft is the JSON Schema version of ExportJSONSchema output.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// ExportMarkdown writes a Markdown table of all defined variables with
+// their types, required flags, default values, descriptions and validation
+// summaries.
+func (c *Config) ExportMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Name | Type | Required | Default | Description | Validation |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, v := range c.variables {
+		required := "no"
+		if v.Required {
+			required = "yes"
+		}
+		d := defaultString(v)
+		if d != "" {
+			d = "`" + d + "`"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s |\n",
+			v.Name,
+			markdownCell(typeName(v)),
+			required,
+			markdownCell(d),
+			markdownCell(v.Description),
+			markdownCell(strings.Join(validationSummary(v), ", ")))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes the text to put it into a Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// ExportJSONSchema writes JSON Schema of config files: an object with a
+// property for every defined variable. Properties have JSON types of the
+// variables, e.g. INT variables are integers and SLICE variables are arrays,
+// like values of config files read by FileLookuper. Validation funcs which
+// could be expressed, like ValidateStringRegexpMatch or ValidateBetween, are
+// added as JSON Schema keywords. Defaults of sensitive variables are omitted.
+//
+// Environment variables are strings, use ExportEnvJSONSchema to validate
+// them.
+func (c *Config) ExportJSONSchema(w io.Writer) error {
+	return c.exportSchema(w, variableSchema)
+}
+
+// ExportEnvJSONSchema writes JSON Schema of environment variables: an
+// object with a string property for every defined variable. Values of
+// numeric, bool and duration variables are checked with patterns or enums
+// of the accepted formats, e.g. INT variables match ^[+-]?[0-9]+$. Only
+// validation funcs of string variables are added as JSON Schema keywords,
+// since numeric keywords don't apply to strings. Defaults are formatted
+// like environment variable values, defaults of sensitive variables are
+// omitted.
+func (c *Config) ExportEnvJSONSchema(w io.Writer) error {
+	return c.exportSchema(w, envVariableSchema)
+}
+
+// exportSchema writes JSON Schema of an object with a property for every
+// defined variable returned by schemaFunc.
+func (c *Config) exportSchema(w io.Writer, schemaFunc func(v *Variable) map[string]interface{}) error {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, v := range c.variables {
+		properties[v.Name] = schemaFunc(v)
+		if v.Required {
+			required = append(required, v.Name)
+		}
+	}
+	schema := map[string]interface{}{
+		"$schema":    JSONSchemaDraft,
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(schema)
+}
+
+// variableSchema returns JSON Schema of the variable.
+func variableSchema(v *Variable) map[string]interface{} {
+	schema := typeSchema(v.valueType)
+	switch v.valueType {
+	case SLICE:
+		schema["items"] = elemSchema(v)
+	case MAP:
+		schema["additionalProperties"] = elemSchema(v)
+	}
+	if v.Description != "" {
+		schema["description"] = v.Description
+	}
+	if v.Sensitive {
+		schema["writeOnly"] = true
+	} else if v.Default != nil {
+		if d, err := defaultValue(v); err == nil {
+			schema["default"] = jsonValue(v, v.valueType, d)
+		}
+	}
+	var funcs []func(value interface{}) error
+	if v.ValidationFunc != nil {
+		funcs = append(funcs, v.ValidationFunc)
+	}
+	for _, f := range append(funcs, v.Validators...) {
+		if p := lookupValidator(f); p.keywords != nil {
+			mergeKeywords(schema, p.keywords)
+		}
+	}
+	return schema
+}
+
+// envVariableSchema returns JSON Schema of the environment variable value.
+func envVariableSchema(v *Variable) map[string]interface{} {
+	schema := map[string]interface{}{"type": "string"}
+	switch v.valueType {
+	case SLICE:
+		if p := envPattern(v, v.elemType); p != "" && !v.TrimSpace && !v.SkipEmpty {
+			sep := regexp.QuoteMeta(separator(v.Separator, defaultSeparator))
+			schema["pattern"] = "^(" + p + "(" + sep + p + ")*)?$"
+		}
+	case MAP:
+		sep := separator(v.Separator, defaultSeparator)
+		kvSep := separator(v.KeyValueSeparator, defaultKeyValueSeparator)
+		p := envPattern(v, v.elemType)
+		if v.elemType == STRING {
+			p = "[^" + regexp.QuoteMeta(sep) + "]*"
+		}
+		if p != "" && !v.TrimSpace && !v.SkipEmpty && len(sep) == 1 && len(kvSep) == 1 {
+			pair := "[^" + regexp.QuoteMeta(sep+kvSep) + "]+" + regexp.QuoteMeta(kvSep) + p
+			schema["pattern"] = "^(" + pair + "(" + regexp.QuoteMeta(sep) + pair + ")*)?$"
+		}
+	case BOOL:
+		schema["enum"] = []string{"1", "t", "T", "TRUE", "true", "True", "0", "f", "F", "FALSE", "false", "False"}
+	case URL:
+		schema["format"] = "uri"
+	case IP:
+		schema["anyOf"] = []interface{}{map[string]interface{}{"format": "ipv4"}, map[string]interface{}{"format": "ipv6"}}
+	default:
+		if p := envPattern(v, v.valueType); p != "" {
+			schema["pattern"] = "^" + p + "$"
+		}
+	}
+	if v.Description != "" {
+		schema["description"] = v.Description
+	}
+	if v.Sensitive {
+		schema["writeOnly"] = true
+	} else if v.Default != nil {
+		if d, err := defaultValue(v); err == nil {
+			schema["default"] = formatValue(v, d.Interface())
+		}
+	}
+	switch v.valueType {
+	case STRING, SECRET, URL:
+		var funcs []func(value interface{}) error
+		if v.ValidationFunc != nil {
+			funcs = append(funcs, v.ValidationFunc)
+		}
+		for _, f := range append(funcs, v.Validators...) {
+			if p := lookupValidator(f); p.keywords != nil {
+				mergeKeywords(schema, p.keywords)
+			}
+		}
+	}
+	return schema
+}
+
+// envPattern returns regular expression of environment variable values of
+// the value type without anchors. It's empty if values are not checked.
+func envPattern(v *Variable, t valueType) string {
+	switch t {
+	case INT, INT8, INT16, INT32, INT64:
+		if v.AllowBasePrefix {
+			return "[+-]?(0[xX][0-9a-fA-F_]+|0[oO]?[0-7_]+|0[bB][01_]+|[0-9][0-9_]*)"
+		}
+		return "[+-]?[0-9]+"
+	case UINT, UINT8, UINT16, UINT32, UINT64:
+		if v.AllowBasePrefix {
+			return "(0[xX][0-9a-fA-F_]+|0[oO]?[0-7_]+|0[bB][01_]+|[0-9][0-9_]*)"
+		}
+		return "[0-9]+"
+	case FLOAT32, FLOAT64:
+		return "[+-]?([0-9]+(\\.[0-9]*)?|\\.[0-9]+)([eE][+-]?[0-9]+)?"
+	case DURATION:
+		return "([+-]?[0-9]+|[+-]?(([0-9]+(\\.[0-9]*)?|\\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)"
+	case BOOL:
+		return "(1|t|T|TRUE|true|True|0|f|F|FALSE|false|False)"
+	}
+	return ""
+}
+
+// separator returns the separator or the default one if it's not set.
+func separator(sep, def string) string {
+	if sep == "" {
+		return def
+	}
+	return sep
+}
+
+// elemSchema returns JSON Schema of SLICE elements or MAP values.
+func elemSchema(v *Variable) map[string]interface{} {
+	schema := typeSchema(v.elemType)
+	if v.ElementValidationFunc != nil {
+		if p := lookupValidator(v.ElementValidationFunc); p.keywords != nil {
+			mergeKeywords(schema, p.keywords)
+		}
+	}
+	return schema
+}
+
+// typeSchema returns JSON Schema of the value type.
+func typeSchema(t valueType) map[string]interface{} {
+	switch t {
+	case INT, INT8, INT16, INT32, INT64:
+		return map[string]interface{}{"type": "integer"}
+	case UINT, UINT8, UINT16, UINT32, UINT64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case FLOAT32, FLOAT64:
+		return map[string]interface{}{"type": "number"}
+	case BOOL:
+		return map[string]interface{}{"type": "boolean"}
+	case SLICE:
+		return map[string]interface{}{"type": "array"}
+	case MAP:
+		return map[string]interface{}{"type": "object"}
+	case URL:
+		return map[string]interface{}{"type": "string", "format": "uri"}
+	}
+	return map[string]interface{}{"type": "string"}
+}
+
+// jsonValue converts the value of type t to the value of the JSON type of
+// the variable. Values of types which are strings in JSON Schema, like
+// DURATION, are formatted like environment variable values.
+func jsonValue(setting *Variable, t valueType, v reflect.Value) interface{} {
+	switch t {
+	case SLICE:
+		elems := make([]interface{}, v.Len())
+		for i := range elems {
+			elems[i] = jsonValue(setting, setting.elemType, v.Index(i))
+		}
+		return elems
+	case MAP:
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = jsonValue(setting, setting.elemType, iter.Value())
+		}
+		return m
+	case INT, INT8, INT16, INT32, INT64, UINT, UINT8, UINT16, UINT32, UINT64, FLOAT32, FLOAT64, BOOL, STRING:
+		return v.Interface()
+	}
+	return formatValue(setting, v.Interface())
+}
@@ -0,0 +1,119 @@
+package gocfg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigExportMarkdown(t *testing.T) {
+	cfg := newUsageConfig(nil)
+	var b bytes.Buffer
+	assert.Nil(t, cfg.ExportMarkdown(&b))
+	expected := []string{
+		"| Name | Type | Required | Default | Description | Validation |",
+		"| --- | --- | --- | --- | --- | --- |",
+		"| `API_URL` | string | yes |  | API endpoint | starts with 'https', not (contains 'localhost') |",
+		"| `DB_PASSWORD` | string | no | `******` |  |  |",
+		"| `WORKERS` | int | no | `4` | number of workers | between 1 and 16, any of (multiple of 2; one of [1]) |",
+		"| `KAFKA_BROKERS` | []string | no | `a:9092,b:9092` |  | each host:port |",
+		"| `MODE` | string | no |  |  | custom |",
+		"",
+	}
+	assert.Equal(t, strings.Join(expected, "\n"), b.String())
+}
+
+func TestConfigExportJSONSchema(t *testing.T) {
+	cfg := newUsageConfig(nil)
+	var timeout time.Duration
+	var limits map[string]int64
+	cfg.SetDuration(&timeout, &Variable{Name: "REQUEST_TIMEOUT", Default: 30 * time.Second})
+	cfg.SetInt64Map(&limits, &Variable{Name: "TENANT_LIMITS", ElementValidationFunc: ValidateMax(100)})
+	var b bytes.Buffer
+	assert.Nil(t, cfg.ExportJSONSchema(&b))
+	expected := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["API_URL"],
+		"properties": {
+			"API_URL": {
+				"type": "string",
+				"description": "API endpoint",
+				"pattern": "^https",
+				"not": {"pattern": "localhost"}
+			},
+			"DB_PASSWORD": {"type": "string", "writeOnly": true},
+			"KAFKA_BROKERS": {
+				"type": "array",
+				"items": {"type": "string"},
+				"default": ["a:9092", "b:9092"]
+			},
+			"MODE": {"type": "string"},
+			"WORKERS": {
+				"type": "integer",
+				"description": "number of workers",
+				"default": 4,
+				"minimum": 1,
+				"maximum": 16,
+				"anyOf": [{"multipleOf": 2}, {"enum": [1]}]
+			},
+			"REQUEST_TIMEOUT": {"type": "string", "default": "30s"},
+			"TENANT_LIMITS": {
+				"type": "object",
+				"additionalProperties": {"type": "integer", "maximum": 100}
+			}
+		}
+	}`
+	assert.JSONEq(t, expected, b.String())
+}
+
+func TestConfigExportEnvJSONSchema(t *testing.T) {
+	cfg := newUsageConfig(nil)
+	var timeout time.Duration
+	var limits map[string]int64
+	var debug bool
+	cfg.SetDuration(&timeout, &Variable{Name: "REQUEST_TIMEOUT", Default: 30 * time.Second})
+	cfg.SetInt64Map(&limits, &Variable{Name: "TENANT_LIMITS", ElementValidationFunc: ValidateMax(100)})
+	cfg.SetBool(&debug, &Variable{Name: "DEBUG"})
+	var b bytes.Buffer
+	assert.Nil(t, cfg.ExportEnvJSONSchema(&b))
+	expected := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["API_URL"],
+		"properties": {
+			"API_URL": {
+				"type": "string",
+				"description": "API endpoint",
+				"pattern": "^https",
+				"not": {"pattern": "localhost"}
+			},
+			"DB_PASSWORD": {"type": "string", "writeOnly": true},
+			"KAFKA_BROKERS": {"type": "string", "default": "a:9092,b:9092"},
+			"MODE": {"type": "string"},
+			"WORKERS": {
+				"type": "string",
+				"description": "number of workers",
+				"default": "4",
+				"pattern": "^[+-]?[0-9]+$"
+			},
+			"REQUEST_TIMEOUT": {
+				"type": "string",
+				"default": "30s",
+				"pattern": "^([+-]?[0-9]+|[+-]?(([0-9]+(\\.[0-9]*)?|\\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$"
+			},
+			"TENANT_LIMITS": {
+				"type": "string",
+				"pattern": "^([^,=]+=[+-]?[0-9]+(,[^,=]+=[+-]?[0-9]+)*)?$"
+			},
+			"DEBUG": {
+				"type": "string",
+				"enum": ["1", "t", "T", "TRUE", "true", "True", "0", "f", "F", "FALSE", "false", "False"]
+			}
+		}
+	}`
+	assert.JSONEq(t, expected, b.String())
+}
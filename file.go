@@ -0,0 +1,217 @@
+package gocfg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FileLookuper implements EnvLookuper interface. It lookups variables in
+// JSON, YAML or TOML config files.
+//
+// Nested keys are flattened to variable names the same way variable names
+// are formatted, e.g. db.pool.max is DB_POOL_MAX. Arrays of scalars are
+// joined with "," and objects of scalars with "," and "=", so they could be
+// parsed by SLICE and MAP variables with default separators.
+//
+// It's safe for concurrent use.
+type FileLookuper struct {
+	// mu guards vars replaced by Reload
+	mu        sync.RWMutex
+	vars      map[string]string
+	path      string
+	unmarshal func([]byte, *map[string]interface{}) error
+}
+
+// NewFileLookuper reads and parses config file. The format is chosen by the
+// file extension: .json, .yaml, .yml or .toml.
+func NewFileLookuper(path string) (*FileLookuper, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return NewJSONLookuper(path)
+	case ".yaml", ".yml":
+		return NewYAMLLookuper(path)
+	case ".toml":
+		return NewTOMLLookuper(path)
+	}
+	return nil, fmt.Errorf("%s: unsupported config file format", path)
+}
+
+// NewJSONLookuper reads and parses JSON config file.
+func NewJSONLookuper(path string) (*FileLookuper, error) {
+	return newFileLookuper(path, func(data []byte, v *map[string]interface{}) error {
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.UseNumber()
+		err := d.Decode(v)
+		var se *json.SyntaxError
+		if errors.As(err, &se) {
+			line := bytes.Count(data[:se.Offset], []byte("\n")) + 1
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		return err
+	})
+}
+
+// NewYAMLLookuper reads and parses YAML config file.
+func NewYAMLLookuper(path string) (*FileLookuper, error) {
+	return newFileLookuper(path, func(data []byte, v *map[string]interface{}) error {
+		return yaml.Unmarshal(data, v)
+	})
+}
+
+// NewTOMLLookuper reads and parses TOML config file.
+func NewTOMLLookuper(path string) (*FileLookuper, error) {
+	return newFileLookuper(path, func(data []byte, v *map[string]interface{}) error {
+		return toml.Unmarshal(data, v)
+	})
+}
+
+// newFileLookuper reads config file, decodes it with unmarshal and flattens
+// the decoded values.
+func newFileLookuper(path string, unmarshal func([]byte, *map[string]interface{}) error) (*FileLookuper, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err := unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	l := &FileLookuper{vars: map[string]string{}, path: path, unmarshal: unmarshal}
+	if err := l.flatten("", values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Reload implements Reloader interface. It re-reads the config file and
+// returns the lookuper of new variables and func which makes them current.
+// It returns error if the file can't be read or parsed.
+func (l *FileLookuper) Reload() (EnvLookuper, func(), error) {
+	n, err := newFileLookuper(l.path, l.unmarshal)
+	if err != nil {
+		return nil, nil, err
+	}
+	return n, func() {
+		l.mu.Lock()
+		l.vars = n.vars
+		l.mu.Unlock()
+	}, nil
+}
+
+// LookupEnv lookups variable defined in the config file.
+func (l *FileLookuper) LookupEnv(key string) (string, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	v, ok := l.vars[key]
+	return v, ok
+}
+
+// flatten adds variables of the object defined by path.
+func (l *FileLookuper) flatten(path string, values map[string]interface{}) error {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		if err := l.flattenValue(p, values[k]); err != nil {
+			return err
+		}
+	}
+	// objects of scalars are MAP values as well
+	if path == "" {
+		return nil
+	}
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		s, ok := formatScalar(values[k])
+		if !ok {
+			return nil
+		}
+		pairs = append(pairs, k+defaultKeyValueSeparator+s)
+	}
+	return l.set(path, strings.Join(pairs, defaultSeparator))
+}
+
+// flattenValue adds variables of the value defined by path.
+func (l *FileLookuper) flattenValue(path string, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		return l.flatten(path, v)
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = e
+		}
+		return l.flatten(path, m)
+	case []interface{}:
+		elems := make([]string, len(v))
+		for i, e := range v {
+			s, ok := formatScalar(e)
+			if !ok {
+				return fmt.Errorf("%s[%d]: unsupported array element type %T", path, i, e)
+			}
+			elems[i] = s
+		}
+		return l.set(path, strings.Join(elems, defaultSeparator))
+	case []map[string]interface{}:
+		return fmt.Errorf("%s: arrays of tables are not supported", path)
+	}
+	s, ok := formatScalar(value)
+	if !ok {
+		return fmt.Errorf("%s: unsupported value type %T", path, value)
+	}
+	return l.set(path, s)
+}
+
+// set adds variable defined by path.
+func (l *FileLookuper) set(path, value string) error {
+	name := strings.ReplaceAll(path, ".", "_")
+	formatEnvVarName(&name)
+	if _, ok := l.vars[name]; ok {
+		return fmt.Errorf("%s: duplicate variable '%s'", path, name)
+	}
+	l.vars[name] = value
+	return nil
+}
+
+// formatScalar returns string representation of the decoded scalar value.
+func formatScalar(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case json.Number:
+		return v.String(), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true
+	}
+	return "", false
+}
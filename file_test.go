@@ -0,0 +1,116 @@
+package gocfg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFile(t *testing.T, name, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.Nil(t, os.WriteFile(path, []byte(data), 0o600))
+	return path
+}
+
+func TestFileLookuper(t *testing.T) {
+	testcases := []struct {
+		name string
+		data string
+	}{
+		{"config.json", `{
+	"api_url": "https://api.example.com",
+	"db": {"pool": {"max": 10}, "timeout": "5s"},
+	"kafka-brokers": ["a:9092", "b:9092"],
+	"headers": {"X-Env": "prod", "X-Team": "core"},
+	"tracing.enabled": true,
+	"cpu_limit": 1.5
+}`},
+		{"config.yaml", `
+api_url: https://api.example.com
+db:
+  pool:
+    max: 10
+  timeout: 5s
+kafka-brokers:
+  - a:9092
+  - b:9092
+headers:
+  X-Env: prod
+  X-Team: core
+tracing.enabled: true
+cpu_limit: 1.5
+`},
+		{"config.toml", `
+api_url = "https://api.example.com"
+kafka-brokers = ["a:9092", "b:9092"]
+"tracing.enabled" = true
+cpu_limit = 1.5
+
+[db]
+timeout = "5s"
+
+[db.pool]
+max = 10
+
+[headers]
+X-Env = "prod"
+X-Team = "core"
+`},
+	}
+	for _, tc := range testcases {
+		l, err := NewFileLookuper(writeTestFile(t, tc.name, tc.data))
+		assert.Nil(t, err, tc.name)
+
+		cfg := New()
+		cfg.SetEnvLookuper(l)
+		var (
+			apiURL   string
+			poolMax  int
+			brokers  []string
+			headers  map[string]string
+			tracing  bool
+			cpuLimit float32
+		)
+		cfg.SetString(&apiURL, &Variable{Name: "API_URL"})
+		cfg.SetInt(&poolMax, &Variable{Name: "DB_POOL_MAX"})
+		cfg.SetStringSlice(&brokers, &Variable{Name: "KAFKA_BROKERS"})
+		cfg.SetStringMap(&headers, &Variable{Name: "HEADERS"})
+		cfg.SetBool(&tracing, &Variable{Name: "TRACING_ENABLED"})
+		cfg.SetFloat32(&cpuLimit, &Variable{Name: "CPU_LIMIT"})
+		assert.Nil(t, cfg.Parse(), tc.name)
+		assert.Equal(t, "https://api.example.com", apiURL, tc.name)
+		assert.Equal(t, 10, poolMax, tc.name)
+		assert.Equal(t, []string{"a:9092", "b:9092"}, brokers, tc.name)
+		assert.Equal(t, map[string]string{"X-Env": "prod", "X-Team": "core"}, headers, tc.name)
+		assert.Equal(t, true, tracing, tc.name)
+		assert.Equal(t, float32(1.5), cpuLimit, tc.name)
+
+		v, ok := l.LookupEnv("DB")
+		assert.Equal(t, "", v, tc.name)
+		assert.False(t, ok, tc.name)
+		v, _ = l.LookupEnv("DB_TIMEOUT")
+		assert.Equal(t, "5s", v, tc.name)
+	}
+}
+
+func TestFileLookuperErrors(t *testing.T) {
+	testcases := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"config.json", `{"db": {"hosts": ["a", {"b": 1}]}}`, "%s: db.hosts[1]: unsupported array element type map[string]interface {}"},
+		{"config.json", "{\n\"a\": 1,\n}", "%s: line 3: invalid character '}' looking for beginning of object key string"},
+		{"config.yaml", "db:\n  pool_max: 1\n  pool:\n    max: 2\n", "%s: db.pool_max: duplicate variable 'DB_POOL_MAX'"},
+		{"config.toml", "[[servers]]\nname = \"a\"\n", "%s: servers: arrays of tables are not supported"},
+		{"config.ini", "a=1", "%s: unsupported config file format"},
+	}
+	for _, tc := range testcases {
+		path := writeTestFile(t, tc.name, tc.data)
+		_, err := NewFileLookuper(path)
+		assert.EqualError(t, err, fmt.Sprintf(tc.err, path), tc.name)
+	}
+}
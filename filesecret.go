@@ -0,0 +1,119 @@
+package gocfg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMaxFileSize limits the size of files read by variables with
+// AllowFile and by DirLookuper.
+const DefaultMaxFileSize = 1 << 20
+
+// fileSuffix is a suffix of variables which hold a path to the file with
+// the value, e.g. DB_PASSWORD_FILE.
+const fileSuffix = "_FILE"
+
+// errFileTooLarge is returned if the file exceeds the size limit.
+var errFileTooLarge = errors.New("file is too large")
+
+// EnvLookuperWithError is implemented by EnvLookupers which can fail to
+// read an existing value. Config prefers LookupEnvWithError to LookupEnv.
+type EnvLookuperWithError interface {
+	EnvLookuper
+	LookupEnvWithError(key string) (string, bool, error)
+}
+
+// DirLookuper implements EnvLookuperWithError interface. It lookups
+// variables in a directory with one file per variable, like a mounted
+// Kubernetes secret volume. The file name is the variable name, it's lower
+// case or lower kebab-case form, e.g. DB_PASSWORD, db_password or
+// db-password. Trailing newlines are trimmed.
+type DirLookuper struct {
+	Dir         string
+	MaxFileSize int64
+}
+
+// NewDirLookuper returns DirLookuper with DefaultMaxFileSize limit.
+func NewDirLookuper(dir string) *DirLookuper {
+	return &DirLookuper{Dir: dir, MaxFileSize: DefaultMaxFileSize}
+}
+
+// LookupEnv lookups variable file. Unreadable files are ignored.
+func (dl *DirLookuper) LookupEnv(key string) (string, bool) {
+	v, ok, err := dl.LookupEnvWithError(key)
+	if err != nil {
+		return "", false
+	}
+	return v, ok
+}
+
+// LookupEnvWithError lookups variable file. It returns error if the file
+// exists but can't be read or exceeds the size limit.
+func (dl *DirLookuper) LookupEnvWithError(key string) (string, bool, error) {
+	if key == "" || strings.ContainsAny(key, `/\`) || strings.HasPrefix(key, ".") {
+		return "", false, nil
+	}
+	names := []string{key, strings.ToLower(key), strings.ToLower(strings.ReplaceAll(key, "_", "-"))}
+	for _, name := range names {
+		v, err := readValueFile(filepath.Join(dl.Dir, name), dl.MaxFileSize)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("variable '%s' file '%s' cannot be read: %w", key, filepath.Join(dl.Dir, name), err)
+		}
+		return v, true, nil
+	}
+	return "", false, nil
+}
+
+// SetMaxFileSize sets the size limit of files read by variables with
+// AllowFile. It's DefaultMaxFileSize by default.
+func (c *Config) SetMaxFileSize(size int64) {
+	c.maxFileSize = size
+}
+
+// lookupFile lookups for the NAME_FILE variable and reads the file it
+// points to.
+func (c *Config) lookupFile(setting *Variable) (string, bool, error) {
+	file := &Variable{Name: setting.Name + fileSuffix}
+	path, ok, err := c.lookupSources(file)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	v, err := readValueFile(path, c.maxFileSize)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, fmt.Errorf("variable '%s' file '%s' is missing", file.Name, path)
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("variable '%s' file '%s' cannot be read: %w", file.Name, path, err)
+	}
+	setting.source = file.source
+	return v, true, nil
+}
+
+// readValueFile reads the file content and trims trailing newlines. Zero
+// or negative size limit means DefaultMaxFileSize.
+func readValueFile(path string, maxSize int64) (string, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxFileSize
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > maxSize {
+		return "", fmt.Errorf("%w, limit is %d bytes", errFileTooLarge, maxSize)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
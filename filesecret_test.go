@@ -0,0 +1,92 @@
+package gocfg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigAllowFile(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "db")
+	large := filepath.Join(dir, "large")
+	assert.Nil(t, os.WriteFile(secret, []byte("s3cr3t\n"), 0o600))
+	assert.Nil(t, os.WriteFile(large, []byte(strings.Repeat("x", 11)), 0o600))
+	missing := filepath.Join(dir, "missing")
+
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"DB_PASSWORD":           "plain",
+			"DB_PASSWORD_FILE":      secret,
+			"API_TOKEN_FILE":        secret,
+			"MISSING_TOKEN_FILE":    missing,
+			"DIRECTORY_TOKEN_FILE":  dir,
+			"LARGE_TOKEN_FILE":      large,
+			"DISABLED_TOKEN_FILE":   secret,
+			"REQUIRED_TOKEN_SOURCE": "none",
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		variable *Variable
+		err      error
+		value    string
+	}{
+		{"variable wins over file", &Variable{Name: "DB_PASSWORD", AllowFile: true}, nil, "plain"},
+		{"read from file", &Variable{Name: "API_TOKEN", AllowFile: true}, nil, "s3cr3t"},
+		{"file is disabled", &Variable{Name: "DISABLED_TOKEN"}, nil, ""},
+		{"missing file", &Variable{Name: "MISSING_TOKEN", AllowFile: true}, NewParseErrors(fmt.Errorf("variable 'MISSING_TOKEN_FILE' file '%s' is missing", missing)), ""},
+		{"large file", &Variable{Name: "LARGE_TOKEN", AllowFile: true}, NewParseErrors(fmt.Errorf("variable 'LARGE_TOKEN_FILE' file '%s' cannot be read: %w", large, fmt.Errorf("%w, limit is 10 bytes", errFileTooLarge))), ""},
+		{"missing required var", &Variable{Name: "REQUIRED_TOKEN", AllowFile: true, Required: true}, NewParseErrors(&MissingError{Name: "REQUIRED_TOKEN"}), ""},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(env)
+		cfg.SetMaxFileSize(10)
+		var value string
+		cfg.SetString(&value, tc.variable)
+		err := cfg.Parse()
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s, Error: '%+v'", tc.name, err))
+		assert.Equal(t, value, tc.value, fmt.Sprintf("Test case: %s", tc.name))
+	}
+
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	var value string
+	cfg.SetString(&value, &Variable{Name: "DIRECTORY_TOKEN", AllowFile: true})
+	assert.Contains(t, fmt.Sprint(cfg.Parse()), fmt.Sprintf("variable 'DIRECTORY_TOKEN_FILE' file '%s' cannot be read", dir))
+}
+
+func TestDirLookuper(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "db-password"), []byte("s3cr3t\r\n"), 0o600))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "API_TOKEN"), []byte("token"), 0o600))
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "broken"), 0o700))
+
+	l := NewDirLookuper(dir)
+	cfg := New()
+	cfg.SetEnvLookuper(&EnvLookuperMock{})
+	cfg.AddSource("secrets", l, 1)
+	var password, token, broken, missing string
+	cfg.SetString(&password, &Variable{Name: "DB_PASSWORD"})
+	cfg.SetString(&token, &Variable{Name: "API_TOKEN"})
+	cfg.SetString(&missing, &Variable{Name: "MISSING"})
+	assert.Nil(t, cfg.Parse())
+	assert.Equal(t, "s3cr3t", password)
+	assert.Equal(t, "token", token)
+	assert.Equal(t, "", missing)
+	source, _ := cfg.Source("DB_PASSWORD")
+	assert.Equal(t, "secrets", source)
+
+	cfg.SetString(&broken, &Variable{Name: "BROKEN"})
+	assert.Contains(t, fmt.Sprint(cfg.Parse()), fmt.Sprintf("variable 'BROKEN' file '%s' cannot be read", filepath.Join(dir, "broken")))
+
+	_, ok := l.LookupEnv("../API_TOKEN")
+	assert.False(t, ok)
+}
@@ -0,0 +1,107 @@
+package gocfg
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+// flagValue implements flag.Value interface. It holds a raw variable value
+// which is converted by Parse().
+type flagValue struct {
+	value  string
+	isSet  bool
+	isBool bool
+}
+
+// String returns the flag value or the variable default value if the flag
+// was not set.
+func (fv *flagValue) String() string {
+	return fv.value
+}
+
+// Set sets the flag value.
+func (fv *flagValue) Set(value string) error {
+	fv.value = value
+	fv.isSet = true
+	return nil
+}
+
+// IsBoolFlag allows to set BOOL variables without value, e.g. --debug.
+func (fv *flagValue) IsBoolFlag() bool {
+	return fv.isBool
+}
+
+// flagLookuper implements EnvLookuper interface. It lookups values of the
+// flags which were set.
+type flagLookuper struct {
+	values map[string]*flagValue
+}
+
+// LookupEnv returns the flag value of the variable.
+func (fl *flagLookuper) LookupEnv(key string) (string, bool) {
+	fv, ok := fl.values[key]
+	if !ok || !fv.isSet {
+		return "", false
+	}
+	return fv.value, true
+}
+
+// BindFlags registers a flag for every defined variable. Flag names are
+// variable names in kebab-case, e.g. API_URL is --api-url. Flags are the
+// SourceFlag source which overrides all other sources.
+//
+// It should be called after all variables were defined. If the flag set was
+// not parsed, Parse() parses it with os.Args[1:] or arguments set by SetArgs
+// and adds flag errors, like unknown flags, to the parsing errors. Create the
+// flag set with flag.ContinueOnError to get them.
+func (c *Config) BindFlags(fs *flag.FlagSet) {
+	fl := &flagLookuper{values: map[string]*flagValue{}}
+	for _, v := range c.variables {
+		fv := &flagValue{isBool: v.valueType == BOOL}
+		if v.Default != nil {
+			fv.value = displayValue(v, formatValue(v, v.Default))
+		}
+		fs.Var(fv, flagName(v.Name), flagUsage(v))
+		fl.values[v.Name] = fv
+	}
+	c.flags = fs
+	c.AddSource(SourceFlag, fl, math.MaxInt32)
+}
+
+// SetArgs sets command-line arguments which are parsed by the flag set
+// bound with BindFlags. It's os.Args[1:] by default.
+func (c *Config) SetArgs(args []string) {
+	c.args = args
+}
+
+// parseFlags parses the bound flag set if it was not parsed yet.
+func (c *Config) parseFlags() error {
+	if c.flags == nil || c.flags.Parsed() {
+		return nil
+	}
+	args := c.args
+	if args == nil {
+		args = os.Args[1:]
+	}
+	return c.flags.Parse(args)
+}
+
+// flagName returns the flag name of the variable.
+func flagName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+}
+
+// flagUsage returns the flag help text of the variable.
+func flagUsage(v *Variable) string {
+	u := fmt.Sprintf("sets %s variable", v.Name)
+	if v.Description != "" {
+		u = v.Description
+	}
+	if v.Required {
+		u += " (required)"
+	}
+	return u
+}
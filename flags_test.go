@@ -0,0 +1,81 @@
+package gocfg
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func TestConfigBindFlags(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"API_URL":   "https://api.example.com",
+			"LOG_LEVEL": "DEBUG",
+		},
+	}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	var apiURL, logLevel string
+	var tracing bool
+	var timeout time.Duration
+	var brokers []string
+	cfg.SetString(&apiURL, &Variable{Name: "API_URL", Required: true})
+	cfg.SetString(&logLevel, &Variable{Name: "LOG_LEVEL"})
+	cfg.SetBool(&tracing, &Variable{Name: "TRACING_ENABLED"})
+	cfg.SetDuration(&timeout, &Variable{Name: "REQUEST_TIMEOUT", Default: 5 * time.Second})
+	cfg.SetStringSlice(&brokers, &Variable{Name: "KAFKA_BROKERS", Default: []string{"a:9092", "b:9092"}})
+
+	fs := newTestFlagSet()
+	cfg.BindFlags(fs)
+	cfg.SetArgs([]string{"--api-url", "https://flag.example.com", "--tracing-enabled", "--request-timeout=10s"})
+
+	f := fs.Lookup("api-url")
+	assert.Equal(t, "sets API_URL variable (required)", f.Usage)
+	assert.Equal(t, "5s", fs.Lookup("request-timeout").DefValue)
+	assert.Equal(t, "a:9092,b:9092", fs.Lookup("kafka-brokers").DefValue)
+
+	assert.Nil(t, cfg.Parse())
+	assert.Equal(t, "https://flag.example.com", apiURL)
+	assert.Equal(t, "DEBUG", logLevel)
+	assert.Equal(t, true, tracing)
+	assert.Equal(t, 10*time.Second, timeout)
+	assert.Equal(t, []string{"a:9092", "b:9092"}, brokers)
+
+	source, _ := cfg.Source("API_URL")
+	assert.Equal(t, SourceFlag, source)
+	source, _ = cfg.Source("LOG_LEVEL")
+	assert.Equal(t, SourceEnv, source)
+	source, _ = cfg.Source("REQUEST_TIMEOUT")
+	assert.Equal(t, SourceFlag, source)
+}
+
+func TestConfigBindFlagsErrors(t *testing.T) {
+	testcases := []struct {
+		name string
+		args []string
+		err  error
+	}{
+		{"unknown flag", []string{"--unknown"}, NewParseErrors(errors.New("flag provided but not defined: -unknown"))},
+		{"malformed value", []string{"--batch-size", "many"}, NewParseErrors(&TypeError{Name: "BATCH_SIZE", Value: "many", Type: "int", Err: strconv.ErrSyntax})},
+	}
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(&EnvLookuperMock{})
+		var batchSize int
+		cfg.SetInt(&batchSize, &Variable{Name: "BATCH_SIZE", Default: 100})
+		cfg.BindFlags(newTestFlagSet())
+		cfg.SetArgs(tc.args)
+		assert.Equal(t, tc.err, cfg.Parse(), tc.name)
+	}
+}
@@ -0,0 +1,103 @@
+package gocfg
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ValidationFunc validates variable value of type T.
+type ValidationFunc[T any] func(value T) error
+
+// VarOpts defines a variable registered by Set. Nil Default means the
+// variable has no default value, zero values are set with Ptr, e.g.
+// Default: gocfg.Ptr(false). Validators are typed funcs, they are shown as
+// custom in usage and docs. Predefined validation funcs set as
+// ValidationFunc are shown with their descriptions.
+type VarOpts[T any] struct {
+	Name       string
+	Default    *T
+	Required   bool
+	Sensitive  bool
+	Validators []ValidationFunc[T]
+	// Options of Variable with the same names.
+	Description       string
+	ValidationFunc    func(value interface{}) error
+	AllowBasePrefix   bool
+	DurationUnit      time.Duration
+	Separator         string
+	KeyValueSeparator string
+	TrimSpace         bool
+	SkipEmpty         bool
+	AllowFile         bool
+}
+
+// Set adds variable of type T to config. It requiers a pointer to the
+// go variable to assign value after parsing. Default value and validators
+// are checked by the compiler, so they can't have a wrong type.
+//
+// It panics if T is not supported by any of Config setters.
+func Set[T any](c *Config, p *T, opts VarOpts[T]) {
+	if !c.setPointer(p, newVariable(opts)) {
+		panic(fmt.Sprintf("gocfg: unsupported variable type %T", p))
+	}
+}
+
+// newVariable returns Variable defined by opts.
+func newVariable[T any](opts VarOpts[T]) *Variable {
+	setting := &Variable{
+		Name:              opts.Name,
+		Required:          opts.Required,
+		Sensitive:         opts.Sensitive,
+		AllowBasePrefix:   opts.AllowBasePrefix,
+		DurationUnit:      opts.DurationUnit,
+		Separator:         opts.Separator,
+		KeyValueSeparator: opts.KeyValueSeparator,
+		TrimSpace:         opts.TrimSpace,
+		SkipEmpty:         opts.SkipEmpty,
+		AllowFile:         opts.AllowFile,
+		Description:       opts.Description,
+		ValidationFunc:    opts.ValidationFunc,
+	}
+	if opts.Default != nil {
+		setting.Default = *opts.Default
+	}
+	for _, f := range opts.Validators {
+		setting.Validators = append(setting.Validators, f.Untyped())
+	}
+	return setting
+}
+
+// Ptr returns a pointer to the value. It's used to set VarOpts.Default.
+func Ptr[T any](value T) *T {
+	return &value
+}
+
+// Untyped returns ValidationFunc which could be used as
+// Variable.ValidationFunc. Values of other types are converted to T, e.g.
+// FLOAT32 variables are validated as float64. Values which can't be
+// converted to T fail validation.
+func (f ValidationFunc[T]) Untyped() func(value interface{}) error {
+	return func(value interface{}) error {
+		v, ok := value.(T)
+		if !ok {
+			t := reflect.TypeOf((*T)(nil)).Elem()
+			rv := reflect.ValueOf(value)
+			// numbers are convertible to strings as runes, it's a mismatch
+			if !rv.IsValid() || !rv.Type().ConvertibleTo(t) || t.Kind() == reflect.String && rv.Kind() != reflect.String {
+				return fmt.Errorf("value of type %T is not %s", value, t)
+			}
+			v = rv.Convert(t).Interface().(T)
+		}
+		return f(v)
+	}
+}
+
+// Typed returns ValidationFunc[T] which calls untyped validation func f,
+// e.g. Typed[string](ValidateStringHasPrefix("https")). Typed funcs can't
+// carry descriptions, so they are shown as custom.
+func Typed[T any](f func(value interface{}) error) ValidationFunc[T] {
+	return func(value T) error {
+		return f(value)
+	}
+}
@@ -0,0 +1,109 @@
+package gocfg
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"API_URL":     "https://api.example.com",
+			"CPU_LIMIT":   "1.5",
+			"BATCH_SIZE":  "5000",
+			"KAFKA_TOPIC": "events;audit",
+		},
+	}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	var (
+		apiURL    string
+		cpuLimit  float32
+		batchSize int
+		timeout   time.Duration
+		topics    []string
+		level     logLevel
+	)
+	Set(cfg, &apiURL, VarOpts[string]{
+		Name:       "API_URL",
+		Required:   true,
+		Validators: []ValidationFunc[string]{Typed[string](ValidateStringHasPrefix("https"))},
+	})
+	Set(cfg, &cpuLimit, VarOpts[float32]{
+		Name: "CPU_LIMIT",
+		Validators: []ValidationFunc[float32]{func(value float32) error {
+			if value > 1 {
+				return errors.New("cpu limit is too high")
+			}
+			return nil
+		}},
+	})
+	Set(cfg, &batchSize, VarOpts[int]{
+		Name: "BATCH_SIZE",
+		Validators: []ValidationFunc[int]{func(value int) error {
+			if value > 1000 {
+				return errors.New("batch size is too big")
+			}
+			return nil
+		}},
+	})
+	Set(cfg, &timeout, VarOpts[time.Duration]{Name: "REQUEST_TIMEOUT", Default: Ptr(5 * time.Second)})
+	Set(cfg, &topics, VarOpts[[]string]{Name: "KAFKA_TOPIC", Separator: ";"})
+	Set(cfg, &level, VarOpts[logLevel]{Name: "LOG_LEVEL", Default: Ptr[logLevel](1)})
+
+	err := cfg.Parse()
+	assert.Equal(t, NewParseErrors(
+		&ValidationError{Name: "CPU_LIMIT", Value: "1.5", Err: errors.New("cpu limit is too high")},
+		&ValidationError{Name: "BATCH_SIZE", Value: "5000", Err: errors.New("batch size is too big")},
+	), err)
+	assert.Equal(t, "https://api.example.com", apiURL)
+	assert.Equal(t, float32(1.5), cpuLimit)
+	assert.Equal(t, 5000, batchSize)
+	assert.Equal(t, 5*time.Second, timeout)
+	assert.Equal(t, []string{"events", "audit"}, topics)
+	assert.Equal(t, logLevel(1), level)
+}
+
+func TestSetZeroDefault(t *testing.T) {
+	cfg := New()
+	cfg.SetEnvLookuper(&EnvLookuperMock{vars: map[string]string{}})
+	port, debug := 8080, true
+	Set(cfg, &port, VarOpts[int]{Name: "PORT", Default: Ptr(0)})
+	Set(cfg, &debug, VarOpts[bool]{Name: "DEBUG", Default: Ptr(false)})
+	assert.Nil(t, cfg.Parse())
+	assert.Equal(t, 0, port)
+	assert.Equal(t, false, debug)
+	source, _ := cfg.Source("PORT")
+	assert.Equal(t, SourceDefault, source)
+}
+
+func TestUntypedTypeMismatch(t *testing.T) {
+	cfg := New()
+	cfg.SetEnvLookuper(&EnvLookuperMock{vars: map[string]string{"API_URL": "https://api.example.com", "PORT": "65"}})
+	var apiURL string
+	var port int
+	anyInt := ValidationFunc[int](func(value int) error {
+		return nil
+	})
+	anyString := ValidationFunc[string](func(value string) error {
+		return nil
+	})
+	cfg.SetString(&apiURL, &Variable{Name: "API_URL", ValidationFunc: anyInt.Untyped()})
+	cfg.SetInt(&port, &Variable{Name: "PORT", ValidationFunc: anyString.Untyped()})
+	err := cfg.Parse()
+	assert.Equal(t, NewParseErrors(
+		&ValidationError{Name: "API_URL", Value: "https://api.example.com", Err: errors.New("value of type string is not int")},
+		&ValidationError{Name: "PORT", Value: "65", Err: errors.New("value of type int is not string")},
+	), err)
+}
+
+func TestSetUnsupportedType(t *testing.T) {
+	cfg := New()
+	var value complex64
+	assert.PanicsWithValue(t, "gocfg: unsupported variable type *complex64", func() {
+		Set(cfg, &value, VarOpts[complex64]{Name: "VALUE"})
+	})
+}
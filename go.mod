@@ -1,5 +1,14 @@
 module github.com/sprokhorov/gocfg
 
-go 1.16
+go 1.20
 
-require github.com/stretchr/testify v1.7.0
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+)
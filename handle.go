@@ -0,0 +1,96 @@
+package gocfg
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Handle holds the value of a variable added by NewHandle. Unlike pointers
+// of setters, it's safe to read while Parse() or Reload() runs in another
+// goroutine.
+type Handle[T any] struct {
+	value atomic.Pointer[T]
+}
+
+// Get returns the value assigned by the last successful Parse() or
+// Reload(). It's the zero value of T if the config was not parsed.
+func (h *Handle[T]) Get() T {
+	if p := h.value.Load(); p != nil {
+		return *p
+	}
+	var zero T
+	return zero
+}
+
+// NewHandle adds variable of type T to config like Set and returns the
+// handle of it's value. The value is parsed into a private go variable and
+// stored in the handle after successful Parse() or Reload(), so readers
+// never see values of the failed parsing.
+//
+// It panics if T is not supported by any of Config setters.
+func NewHandle[T any](c *Config, opts VarOpts[T]) *Handle[T] {
+	h := &Handle[T]{}
+	p := new(T)
+	setting := newVariable(opts)
+	setting.store = func() {
+		v := *p
+		h.value.Store(&v)
+	}
+	if !c.setPointer(p, setting) {
+		panic(fmt.Sprintf("gocfg: unsupported variable type %T", p))
+	}
+	return h
+}
+
+// String adds STRING variable to config and returns the handle of it's
+// value.
+func (c *Config) String(name string, opts VarOpts[string]) *Handle[string] {
+	opts.Name = name
+	return NewHandle(c, opts)
+}
+
+// Int adds INT variable to config and returns the handle of it's value.
+func (c *Config) Int(name string, opts VarOpts[int]) *Handle[int] {
+	opts.Name = name
+	return NewHandle(c, opts)
+}
+
+// Int64 adds INT64 variable to config and returns the handle of it's value.
+func (c *Config) Int64(name string, opts VarOpts[int64]) *Handle[int64] {
+	opts.Name = name
+	return NewHandle(c, opts)
+}
+
+// Uint adds UINT variable to config and returns the handle of it's value.
+func (c *Config) Uint(name string, opts VarOpts[uint]) *Handle[uint] {
+	opts.Name = name
+	return NewHandle(c, opts)
+}
+
+// Float64 adds FLOAT64 variable to config and returns the handle of it's
+// value.
+func (c *Config) Float64(name string, opts VarOpts[float64]) *Handle[float64] {
+	opts.Name = name
+	return NewHandle(c, opts)
+}
+
+// Bool adds BOOL variable to config and returns the handle of it's value.
+func (c *Config) Bool(name string, opts VarOpts[bool]) *Handle[bool] {
+	opts.Name = name
+	return NewHandle(c, opts)
+}
+
+// Duration adds DURATION variable to config and returns the handle of it's
+// value.
+func (c *Config) Duration(name string, opts VarOpts[time.Duration]) *Handle[time.Duration] {
+	opts.Name = name
+	return NewHandle(c, opts)
+}
+
+// StringSlice adds SLICE variable of STRING elements to config and returns
+// the handle of it's value.
+func (c *Config) StringSlice(name string, opts VarOpts[[]string]) *Handle[[]string] {
+	opts.Name = name
+	return NewHandle(c, opts)
+}
@@ -0,0 +1,96 @@
+package gocfg
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigHandles(t *testing.T) {
+	env := &EnvLookuperMock{vars: map[string]string{
+		"LOG_LEVEL":     "DEBUG",
+		"WORKERS":       "8",
+		"TIMEOUT":       "30",
+		"KAFKA_BROKERS": "a:9092,b:9092",
+	}}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	logLevel := cfg.String("LOG_LEVEL", VarOpts[string]{Required: true})
+	workers := cfg.Int("WORKERS", VarOpts[int]{Validators: []ValidationFunc[int]{Typed[int](ValidateBetween(1, 16))}})
+	timeout := cfg.Duration("TIMEOUT", VarOpts[time.Duration]{})
+	debug := cfg.Bool("DEBUG", VarOpts[bool]{Default: Ptr(true)})
+	brokers := cfg.StringSlice("KAFKA_BROKERS", VarOpts[[]string]{})
+	ratio := NewHandle(cfg, VarOpts[float32]{Name: "RATIO", Default: Ptr[float32](0.5)})
+
+	// zero values before parsing
+	assert.Equal(t, "", logLevel.Get())
+	assert.Equal(t, 0, workers.Get())
+
+	assert.Nil(t, cfg.Parse())
+	assert.Equal(t, "DEBUG", logLevel.Get())
+	assert.Equal(t, 8, workers.Get())
+	assert.Equal(t, 30*time.Second, timeout.Get())
+	assert.Equal(t, true, debug.Get())
+	assert.Equal(t, []string{"a:9092", "b:9092"}, brokers.Get())
+	assert.Equal(t, float32(0.5), ratio.Get())
+
+	// values of the failed parsing are not stored
+	env.vars["LOG_LEVEL"] = "INFO"
+	env.vars["WORKERS"] = "32"
+	assert.True(t, errors.Is(cfg.Parse(), ErrValidation))
+	assert.Equal(t, "DEBUG", logLevel.Get())
+	assert.Equal(t, 8, workers.Get())
+
+	env.vars["WORKERS"] = "4"
+	assert.Nil(t, cfg.Reload())
+	assert.Equal(t, "INFO", logLevel.Get())
+	assert.Equal(t, 4, workers.Get())
+}
+
+func TestConfigHandlesConcurrentReload(t *testing.T) {
+	env := &EnvLookuperMock{vars: map[string]string{"WORKERS": "1", "KAFKA_BROKERS": "a:9092"}}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	workers := cfg.Int("WORKERS", VarOpts[int]{})
+	brokers := cfg.StringSlice("KAFKA_BROKERS", VarOpts[[]string]{})
+	assert.Nil(t, cfg.Parse())
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if w := workers.Get(); w < 1 || w > 100 {
+					t.Errorf("unexpected workers value %d", w)
+					return
+				}
+				if b := brokers.Get(); len(b) != 1 {
+					t.Errorf("unexpected brokers value %v", b)
+					return
+				}
+			}
+		}()
+	}
+	for i := 1; i <= 100; i++ {
+		env.vars = map[string]string{"WORKERS": strconv.Itoa(i), "KAFKA_BROKERS": "b:9092"}
+		if i%2 == 0 {
+			assert.Nil(t, cfg.Reload())
+		} else {
+			assert.Nil(t, cfg.Parse())
+		}
+	}
+	close(done)
+	wg.Wait()
+	assert.Equal(t, 100, workers.Get())
+}
@@ -1,196 +1,401 @@
 package gocfg
 
 import (
+	"encoding"
+	"errors"
 	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 )
 
-// parseString lookups for the environment variable and assign it
-// to the pointer. It return error if:
+const (
+	// defaultSeparator splits SLICE and MAP values if Variable.Separator
+	// is not set.
+	defaultSeparator = ","
+	// defaultKeyValueSeparator splits MAP pairs if Variable.KeyValueSeparator
+	// is not set.
+	defaultKeyValueSeparator = "="
+)
+
+// parseVariable lookups for the environment variable, converts it to the
+// variable type and assign it to the pointer. SLICE elements and MAP values
+// are validated by ElementValidationFunc, the whole value by ValidationFunc.
+// It return error if:
 // - variable was not defined but it's required
-// - default value has a wrong type
-// - variable values has a wrong type
-func (c *Config) parseString(setting *Variable) error {
-	v, ok := c.env.LookupEnv(setting.Name)
+// - default value has a wrong type or is not valid
+// - variable values has a wrong type or is out of range
+// - SLICE or MAP value is malformed
+// - variable file can't be read
+func (c *Config) parseVariable(setting *Variable) error {
+	setting.raw, setting.value = "", nil
+	v, ok, err := c.lookup(setting)
+	if err != nil {
+		return err
+	}
 	if setting.Required && !ok {
-		return fmt.Errorf("'%s' variable is missing", setting.Name)
+		return &MissingError{Name: setting.Name}
 	}
-	p := setting.pointer.(*string)
+	p := reflect.ValueOf(setting.pointer).Elem()
 	// set a default value if env var was not defined
 	if !ok && setting.Default != nil {
-		d, ok := setting.Default.(string)
-		if !ok {
-			return fmt.Errorf("variable '%s' has a wrong default value type", setting.Name)
+		d, err := checkDefault(setting)
+		if d.IsValid() {
+			p.Set(d)
+			setting.source = SourceDefault
+			setting.value = d.Interface()
 		}
-		*p = d
-		return nil
+		return err
 	}
-	*p = v
-	// validate value
-	if setting.ValidationFunc != nil {
-		return setting.ValidationFunc(v)
+	value, err := convertValue(setting, setting.valueType, v)
+	if err != nil {
+		return err
+	}
+	p.Set(reflect.ValueOf(value))
+	if ok {
+		setting.raw, setting.value = v, value
+	}
+	if err := validateValue(setting, p, value); err != nil {
+		return newValidationError(setting, err, v)
 	}
 	return nil
 }
 
-// parseInt lookups for the environment variable and assign it
-// to the pointer. It return error if:
-// - variable was not defined but it's required
-// - default value has a wrong type
-// - variable values has a wrong type
-func (c *Config) parseInt(setting *Variable) error {
-	v, ok := c.env.LookupEnv(setting.Name)
-	if setting.Required && !ok {
-		return fmt.Errorf("'%s' variable is missing", setting.Name)
+// validateValue validates SLICE elements and MAP values with
+// ElementValidationFunc and the whole value with ValidationFunc and
+// Validators. It returns ValidationErrors if more than one validation failed.
+func validateValue(setting *Variable, p reflect.Value, value interface{}) error {
+	var errs ValidationErrors
+	if setting.ElementValidationFunc != nil {
+		validateElements(p, setting.ElementValidationFunc, &errs)
 	}
-	p := setting.pointer.(*int)
-	// set a default value if env var was not defined
-	if !ok && setting.Default != nil {
-		d, ok := setting.Default.(int)
-		if !ok {
-			return fmt.Errorf("variable '%s' has a wrong default value type", setting.Name)
+	switch v := value.(type) {
+	case float32:
+		// FLOAT32 values are validated as float64 for compatibility
+		value = float64(v)
+	case Secret:
+		// SECRET values are validated as strings to reuse string validators
+		value = string(v)
+	}
+	if setting.ValidationFunc != nil {
+		if err := setting.ValidationFunc(value); err != nil {
+			errs.add(err)
 		}
-		*p = d
-		return nil
 	}
-	iv, err := strconv.Atoi(v)
+	for _, f := range setting.Validators {
+		if err := f(value); err != nil {
+			errs.add(err)
+		}
+	}
+	return errs.err()
+}
+
+// checkDefault converts the variable default value to the variable type and
+// validates it like the environment variable value. The converted value is
+// returned even if it's not valid.
+func checkDefault(setting *Variable) (reflect.Value, error) {
+	d, err := defaultValue(setting)
 	if err != nil {
-		return fmt.Errorf("variable '%s' has a wrong value type", setting.Name)
+		return d, err
 	}
-	*p = iv
-	// validate value
-	if setting.ValidationFunc != nil {
-		return setting.ValidationFunc(iv)
+	if err := validateValue(setting, d, d.Interface()); err != nil {
+		return d, newValidationError(setting, err, formatValue(setting, d.Interface()))
 	}
-	return nil
+	return d, nil
 }
 
-// parseInt64 lookups for the environment variable and assign it
-// to the pointer. It return error if:
-// - variable was not defined but it's required
-// - default value has a wrong type
-// - variable values has a wrong type
-func (c *Config) parseInt64(setting *Variable) error {
-	v, ok := c.env.LookupEnv(setting.Name)
-	if setting.Required && !ok {
-		return fmt.Errorf("'%s' variable is missing", setting.Name)
-	}
-	p := setting.pointer.(*int64)
-	// set a default value if env var was not defined
-	if !ok && setting.Default != nil {
-		d, ok := setting.Default.(int64)
-		if !ok {
-			return fmt.Errorf("variable '%s' has a wrong default value type", setting.Name)
-		}
-		*p = d
-		return nil
+// stringDefaultTypes are value types which defaults could be defined as
+// strings.
+var stringDefaultTypes = map[valueType]bool{
+	TEXT:    true,
+	DECODER: true,
+	SECRET:  true,
+	URL:     true,
+	IP:      true,
+	IPNET:   true,
+}
+
+// defaultValue returns the variable default value converted to the
+// variable type. Defaults of stringDefaultTypes could be defined as
+// strings, URL and IPNET defaults as pointers as well.
+func defaultValue(setting *Variable) (reflect.Value, error) {
+	d := reflect.ValueOf(setting.Default)
+	t := reflect.TypeOf(setting.pointer).Elem()
+	if d.Type() == t {
+		return copyValue(d), nil
 	}
-	iv, err := strconv.ParseInt(v, 10, 64)
-	if err != nil {
-		return fmt.Errorf("variable '%s' has a wrong value type", setting.Name)
+	if d.Kind() == reflect.Ptr && d.Type().Elem() == t && !d.IsNil() && (setting.valueType == URL || setting.valueType == IPNET) {
+		return d.Elem(), nil
 	}
-	*p = iv
-	// validate value
-	if setting.ValidationFunc != nil {
-		return setting.ValidationFunc(iv)
+	if s, ok := setting.Default.(string); ok && stringDefaultTypes[setting.valueType] {
+		v, err := convertScalar(setting, setting.valueType, s)
+		if err != nil {
+			return reflect.Value{}, &DefaultTypeError{Name: setting.Name, Type: t.String(), Err: err}
+		}
+		return reflect.ValueOf(v), nil
 	}
-	return nil
+	return reflect.Value{}, &DefaultTypeError{Name: setting.Name, Type: t.String()}
 }
 
-// parseFloat32 lookups for the environment variable and assign it
-// to the pointer. It return error if:
-// - variable was not defined but it's required
-// - default value has a wrong type
-// - variable values has a wrong type
-func (c *Config) parseFloat32(setting *Variable) error {
-	v, ok := c.env.LookupEnv(setting.Name)
-	if setting.Required && !ok {
-		return fmt.Errorf("'%s' variable is missing", setting.Name)
+// copyValue returns a copy of SLICE and MAP values to avoid sharing them
+// between the default value and the application.
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(s, v)
+		return s
+	case reflect.Map:
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return m
 	}
-	p := setting.pointer.(*float32)
-	// set a default value if env var was not defined
-	if !ok && setting.Default != nil {
-		d, ok := setting.Default.(float32)
-		if !ok {
-			return fmt.Errorf("variable '%s' has a wrong default value type", setting.Name)
+	return v
+}
+
+// validateElements validates SLICE elements and MAP values and adds
+// failures to errs.
+func validateElements(v reflect.Value, f func(value interface{}) error, errs *ValidationErrors) {
+	switch v.Kind() {
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := f(v.Index(i).Interface()); err != nil {
+				errs.add(err)
+			}
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			if err := f(v.MapIndex(k).Interface()); err != nil {
+				errs.add(err)
+			}
 		}
-		*p = d
-		return nil
 	}
-	fv, err := strconv.ParseFloat(v, 32)
+}
+
+// convertValue converts string to the go value of the valueType. It return
+// TypeError if the value has a wrong type or is out of range.
+func convertValue(setting *Variable, t valueType, v string) (interface{}, error) {
+	switch t {
+	case SLICE:
+		return convertSlice(setting, v)
+	case MAP:
+		return convertMap(setting, v)
+	}
+	value, err := convertScalar(setting, t, v)
 	if err != nil {
-		return fmt.Errorf("variable '%s' has a wrong value type", setting.Name)
+		// strconv errors duplicate the raw value
+		var ne *strconv.NumError
+		if errors.As(err, &ne) {
+			err = ne.Err
+		}
+		typ := goType(setting, t).String()
+		if setting.Sensitive {
+			kind := ErrType
+			if errors.Is(err, strconv.ErrRange) {
+				kind = strconv.ErrRange
+			}
+			err = redactError(err, kind, fmt.Sprintf("value '%s' is not %s", RedactedValue, typ))
+		}
+		return nil, &TypeError{Name: setting.Name, Value: displayValue(setting, v), Type: typ, Err: err}
 	}
-	*p = float32(fv)
-	// validate value
-	if setting.ValidationFunc != nil {
-		return setting.ValidationFunc(fv)
+	return value, nil
+}
+
+// goType returns the go type of the variable or of it's SLICE elements and
+// MAP values if t is the element type.
+func goType(setting *Variable, t valueType) reflect.Type {
+	pt := reflect.TypeOf(setting.pointer).Elem()
+	if t != setting.valueType {
+		return pt.Elem()
 	}
-	return nil
+	return pt
 }
 
-// parseFloat64 lookups for the environment variable and assign it
-// to the pointer. It return error if:
-// - variable was not defined but it's required
-// - default value has a wrong type
-// - variable values has a wrong type
-func (c *Config) parseFloat64(setting *Variable) error {
-	v, ok := c.env.LookupEnv(setting.Name)
-	if setting.Required && !ok {
-		return fmt.Errorf("'%s' variable is missing", setting.Name)
+// convertScalar converts string to the go value of the scalar valueType.
+func convertScalar(setting *Variable, t valueType, v string) (interface{}, error) {
+	base := 10
+	if setting.AllowBasePrefix {
+		base = 0
 	}
-	p := setting.pointer.(*float64)
-	// set a default value if env var was not defined
-	if !ok && setting.Default != nil {
-		d, ok := setting.Default.(float64)
-		if !ok {
-			return fmt.Errorf("variable '%s' has a wrong default value type", setting.Name)
+	switch t {
+	case STRING:
+		return v, nil
+	case SECRET:
+		return Secret(v), nil
+	case INT:
+		iv, err := strconv.ParseInt(v, base, 0)
+		return int(iv), err
+	case INT8:
+		iv, err := strconv.ParseInt(v, base, 8)
+		return int8(iv), err
+	case INT16:
+		iv, err := strconv.ParseInt(v, base, 16)
+		return int16(iv), err
+	case INT32:
+		iv, err := strconv.ParseInt(v, base, 32)
+		return int32(iv), err
+	case INT64:
+		return strconv.ParseInt(v, base, 64)
+	case UINT:
+		uv, err := strconv.ParseUint(v, base, 0)
+		return uint(uv), err
+	case UINT8:
+		uv, err := strconv.ParseUint(v, base, 8)
+		return uint8(uv), err
+	case UINT16:
+		uv, err := strconv.ParseUint(v, base, 16)
+		return uint16(uv), err
+	case UINT32:
+		uv, err := strconv.ParseUint(v, base, 32)
+		return uint32(uv), err
+	case UINT64:
+		return strconv.ParseUint(v, base, 64)
+	case FLOAT32:
+		fv, err := strconv.ParseFloat(v, 32)
+		return float32(fv), err
+	case FLOAT64:
+		return strconv.ParseFloat(v, 64)
+	case BOOL:
+		return strconv.ParseBool(v)
+	case DURATION:
+		return parseDurationValue(v, setting.DurationUnit)
+	case TEXT:
+		// decode into a new value to keep the current one on failure
+		p := reflect.New(reflect.TypeOf(setting.pointer).Elem())
+		if err := p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(v)); err != nil {
+			return nil, err
 		}
-		*p = d
-		return nil
+		return p.Elem().Interface(), nil
+	case DECODER:
+		p := reflect.New(reflect.TypeOf(setting.pointer).Elem())
+		if err := p.Interface().(Decoder).Decode(v); err != nil {
+			return nil, err
+		}
+		return p.Elem().Interface(), nil
+	case URL:
+		u, err := url.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme == "" {
+			return nil, errors.New("url scheme is missing")
+		}
+		return *u, nil
+	case IP:
+		ip := net.ParseIP(v)
+		if ip == nil {
+			return nil, errors.New("invalid ip address")
+		}
+		return ip, nil
+	case IPNET:
+		_, n, err := net.ParseCIDR(v)
+		if err != nil {
+			return nil, err
+		}
+		return *n, nil
 	}
-	fv, err := strconv.ParseFloat(v, 64)
-	if err != nil {
-		return fmt.Errorf("variable '%s' has a wrong value type", setting.Name)
+	return nil, fmt.Errorf("unsupported value type %s", t)
+}
+
+// parseDurationValue parses duration string like "1m30s". Bare integers are
+// multiplied by unit, which defaults to time.Second. It returns
+// strconv.ErrRange if the product overflows time.Duration.
+func parseDurationValue(v string, unit time.Duration) (time.Duration, error) {
+	if iv, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if unit == 0 {
+			unit = time.Second
+		}
+		if iv > math.MaxInt64/int64(unit) || iv < math.MinInt64/int64(unit) {
+			return 0, strconv.ErrRange
+		}
+		return time.Duration(iv) * unit, nil
 	}
-	*p = fv
-	// validate value
-	if setting.ValidationFunc != nil {
-		return setting.ValidationFunc(fv)
+	return time.ParseDuration(v)
+}
+
+// convertSlice splits the value by the variable separator and converts
+// elements to the variable element type. Empty value is an empty slice.
+func convertSlice(setting *Variable, v string) (interface{}, error) {
+	s := reflect.MakeSlice(reflect.TypeOf(setting.pointer).Elem(), 0, 0)
+	for _, e := range splitValue(setting, v) {
+		ev, err := convertValue(setting, setting.elemType, e)
+		if err != nil {
+			return nil, err
+		}
+		s = reflect.Append(s, reflect.ValueOf(ev))
 	}
-	return nil
+	return s.Interface(), nil
 }
 
-// parseBool lookups for the environment variable and assign it
-// to the pointer. It return error if:
-// - variable was not defined but it's required
-// - default value has a wrong type
-// - variable values has a wrong type
-func (c *Config) parseBool(setting *Variable) error {
-	v, ok := c.env.LookupEnv(setting.Name)
-	if setting.Required && !ok {
-		return fmt.Errorf("'%s' variable is missing", setting.Name)
+// convertMap splits the value into key/value pairs and converts values to
+// the variable element type. Empty value is an empty map.
+func convertMap(setting *Variable, v string) (interface{}, error) {
+	m := reflect.MakeMap(reflect.TypeOf(setting.pointer).Elem())
+	kvSep := setting.KeyValueSeparator
+	if kvSep == "" {
+		kvSep = defaultKeyValueSeparator
 	}
-	p := setting.pointer.(*bool)
-	// set a default value if env var was not defined
-	if !ok && setting.Default != nil {
-		d, ok := setting.Default.(bool)
-		if !ok {
-			return fmt.Errorf("variable '%s' has a wrong default value type", setting.Name)
+	for _, pair := range splitValue(setting, v) {
+		kv := strings.SplitN(pair, kvSep, 2)
+		if len(kv) != 2 {
+			return nil, malformedPairError(setting, pair)
+		}
+		key, value := kv[0], kv[1]
+		if setting.TrimSpace {
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
 		}
-		*p = d
+		if key == "" {
+			return nil, malformedPairError(setting, pair)
+		}
+		if m.MapIndex(reflect.ValueOf(key)).IsValid() {
+			pair = displayValue(setting, pair)
+			return nil, &TypeError{Name: setting.Name, Value: pair, Type: goType(setting, MAP).String(), Err: &mapError{fmt.Sprintf("a duplicate key '%s'", displayValue(setting, key))}}
+		}
+		ev, err := convertValue(setting, setting.elemType, value)
+		if err != nil {
+			return nil, err
+		}
+		m.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(ev))
+	}
+	return m.Interface(), nil
+}
+
+// malformedPairError returns TypeError of the malformed MAP pair.
+func malformedPairError(setting *Variable, pair string) error {
+	pair = displayValue(setting, pair)
+	return &TypeError{Name: setting.Name, Value: pair, Type: goType(setting, MAP).String(), Err: &mapError{fmt.Sprintf("a malformed pair '%s'", pair)}}
+}
+
+// splitValue splits SLICE and MAP values by the variable separator.
+func splitValue(setting *Variable, v string) []string {
+	if v == "" {
 		return nil
 	}
-	bv, err := strconv.ParseBool(v)
-	if err != nil {
-		return fmt.Errorf("variable '%s' has a wrong value type", setting.Name)
+	sep := setting.Separator
+	if sep == "" {
+		sep = defaultSeparator
 	}
-	*p = bv
-	// validate value
-	if setting.ValidationFunc != nil {
-		return setting.ValidationFunc(bv)
+	var parts []string
+	for _, e := range strings.Split(v, sep) {
+		if setting.TrimSpace {
+			e = strings.TrimSpace(e)
+		}
+		if setting.SkipEmpty && e == "" {
+			continue
+		}
+		parts = append(parts, e)
 	}
-	return nil
+	return parts
 }
 
 // Parse lookups for defined environment variables and asserts their types. It
@@ -200,40 +405,53 @@ func (c *Config) parseBool(setting *Variable) error {
 // - variable was not defined but it's required
 // - default value has a wrong type
 // - variable values has a wrong type
+// - command-line flags bound with BindFlags are malformed
+// - rules added with AddRule and other rule methods are violated
+//
+// It returns ErrHelp if help was enabled by EnableHelp and requested.
+//
+// Values are assigned while variables are parsed, so Parse() should not be
+// called while pointers are read by other goroutines. Use Reload() to update
+// values of the parsed config or Handle values which are safe to read
+// concurrently.
 func (c *Config) Parse() error {
+	if c.help != nil && c.helpRequested() {
+		if err := c.Usage(c.help); err != nil {
+			return err
+		}
+		return ErrHelp
+	}
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.parse(); err != nil {
+		return err
+	}
+	for _, v := range c.variables {
+		if v.store != nil {
+			v.store()
+		}
+	}
+	return nil
+}
+
+// parse parses flags and variables and checks rules.
+func (c *Config) parse() error {
 	errs := NewParseErrors()
+	if err := c.parseFlags(); err != nil {
+		errs.Add(err)
+	}
 	for _, v := range c.variables {
-		switch v.valueType {
-		case STRING:
-			if err := c.parseString(v); err != nil {
-				errs.Add(err)
-				continue
-			}
-		case INT:
-			if err := c.parseInt(v); err != nil {
-				errs.Add(err)
-				continue
-			}
-		case INT64:
-			if err := c.parseInt64(v); err != nil {
-				errs.Add(err)
-				continue
-			}
-		case FLOAT32:
-			if err := c.parseFloat32(v); err != nil {
-				errs.Add(err)
-				continue
-			}
-		case FLOAT64:
-			if err := c.parseFloat64(v); err != nil {
-				errs.Add(err)
-				continue
-			}
-		case BOOL:
-			if err := c.parseBool(v); err != nil {
-				errs.Add(err)
-				continue
-			}
+		v.failed = false
+		if err := c.parseVariable(v); err != nil {
+			v.failed = true
+			errs.Add(err)
+		}
+	}
+	for _, r := range c.rules {
+		if err := c.checkRule(r); err != nil {
+			errs.Add(err)
 		}
 	}
 	if errs.IsNotNil() {
@@ -265,11 +483,40 @@ func (pe *ParseErrors) Error() string {
 	return m
 }
 
-// Add adds error to the list.
+// Add adds error to the list. Every error of ValidationErrors is added
+// separately.
 func (pe *ParseErrors) Add(err error) {
+	if errs, ok := err.(ValidationErrors); ok {
+		pe.errs = append(pe.errs, errs...)
+		return
+	}
 	pe.errs = append(pe.errs, err)
 }
 
+// Errors returns a copy of the errors list.
+func (pe *ParseErrors) Errors() []error {
+	return append([]error{}, pe.errs...)
+}
+
+// Unwrap returns the errors list. It makes errors.Is and errors.As check
+// every error of the list.
+func (pe *ParseErrors) Unwrap() []error {
+	return pe.errs
+}
+
+// Filter returns errors of the kind, which is one of ErrMissing, ErrType,
+// ErrValidation, ErrDefaultType, ErrRule or any other error checked by
+// errors.Is.
+func (pe *ParseErrors) Filter(kind error) []error {
+	var errs []error
+	for _, e := range pe.errs {
+		if errors.Is(e, kind) {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
 // IsNotNil returns true if struct has errors.
 func (pe *ParseErrors) IsNotNil() bool {
 	return len(pe.errs) > 0
@@ -0,0 +1,68 @@
+package gocfg
+
+// RedactedValue replaces values of sensitive variables.
+const RedactedValue = "******"
+
+// Secret is a string which is masked when it's printed, marshaled to JSON
+// or text. Convert it to string or use Value() to get the secret.
+type Secret string
+
+// Value returns the secret.
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String implements fmt.Stringer interface. It returns RedactedValue.
+func (s Secret) String() string {
+	return RedactedValue
+}
+
+// GoString implements fmt.GoStringer interface. It returns RedactedValue.
+func (s Secret) GoString() string {
+	return RedactedValue
+}
+
+// MarshalText implements encoding.TextMarshaler interface. It returns
+// RedactedValue.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(RedactedValue), nil
+}
+
+// displayValue returns RedactedValue if the variable is sensitive.
+func displayValue(setting *Variable, v string) string {
+	if setting.Sensitive {
+		return RedactedValue
+	}
+	return v
+}
+
+// redactedError replaces the error of sensitive variable. The original
+// error is dropped, since it could contain the value in any form, only the
+// kind of error is kept as the cause, so errors.Is works.
+type redactedError struct {
+	msg  string
+	kind error
+}
+
+// Error implements Error method of error interface.
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the kind of error.
+func (e *redactedError) Unwrap() error {
+	return e.kind
+}
+
+// redactError replaces the error of sensitive variable or every error of
+// ValidationErrors with the error of the message and kind.
+func redactError(err, kind error, msg string) error {
+	if errs, ok := err.(ValidationErrors); ok {
+		redacted := make(ValidationErrors, len(errs))
+		for i, e := range errs {
+			redacted[i] = redactError(e, kind, msg)
+		}
+		return redacted
+	}
+	return &redactedError{msg: msg, kind: kind}
+}
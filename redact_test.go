@@ -0,0 +1,145 @@
+package gocfg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecret(t *testing.T) {
+	s := Secret("s3cr3t")
+	assert.Equal(t, "s3cr3t", s.Value())
+	assert.Equal(t, "s3cr3t", string(s))
+	assert.Equal(t, "****** ****** ******", fmt.Sprintf("%s %v %#v", s, s, s))
+	b, err := json.Marshal(struct{ Password Secret }{s})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"Password":"******"}`, string(b))
+}
+
+func TestConfigSensitive(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"DB_PASSWORD":   "pa55word",
+			"API_TOKENS":    "t0ken1,t0ken2",
+			"TENANT_TOKENS": "acme=t0ken1,globex",
+			"PIN":           "1",
+			"RATIO":         "0.50",
+			"TIMEOUT":       "90",
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		variable *Variable
+		set      func(cfg *Config, setting *Variable)
+		err      error
+	}{
+		{"secret validation", &Variable{Name: "DB_PASSWORD", ValidationFunc: ValidateStringRegexpMatch(`^\w{10,}$`)}, func(cfg *Config, setting *Variable) {
+			var value Secret
+			cfg.SetSecret(&value, setting)
+		}, NewParseErrors(&ValidationError{Name: "DB_PASSWORD", Value: RedactedValue, Err: &redactedError{msg: "variable 'DB_PASSWORD' value '******' is not valid", kind: ErrValidation}})},
+		{"sensitive string validation", &Variable{Name: "DB_PASSWORD", Sensitive: true, ValidationFunc: ValidateStringHasPrefix("secret")}, func(cfg *Config, setting *Variable) {
+			var value string
+			cfg.SetString(&value, setting)
+		}, NewParseErrors(&ValidationError{Name: "DB_PASSWORD", Value: RedactedValue, Err: &redactedError{msg: "variable 'DB_PASSWORD' value '******' is not valid", kind: ErrValidation}})},
+		{"sensitive slice elements", &Variable{Name: "API_TOKENS", Sensitive: true, ElementValidationFunc: ValidateStringHasSuffix("1")}, func(cfg *Config, setting *Variable) {
+			var value []string
+			cfg.SetStringSlice(&value, setting)
+		}, NewParseErrors(&ValidationError{Name: "API_TOKENS", Value: RedactedValue, Err: &redactedError{msg: "variable 'API_TOKENS' value '******' is not valid", kind: ErrValidation}})},
+		{"sensitive malformed pair", &Variable{Name: "TENANT_TOKENS", Sensitive: true}, func(cfg *Config, setting *Variable) {
+			var value map[string]string
+			cfg.SetStringMap(&value, setting)
+		}, NewParseErrors(&TypeError{Name: "TENANT_TOKENS", Value: RedactedValue, Type: "map[string]string", Err: &mapError{"a malformed pair '******'"}})},
+		{"sensitive int validation", &Variable{Name: "PIN", Sensitive: true, ValidationFunc: ValidateMin(10)}, func(cfg *Config, setting *Variable) {
+			var value int
+			cfg.SetInt(&value, setting)
+		}, NewParseErrors(&ValidationError{Name: "PIN", Value: RedactedValue, Err: &redactedError{msg: "variable 'PIN' value '******' is not valid", kind: ErrValidation}})},
+		{"sensitive float validation", &Variable{Name: "RATIO", Sensitive: true, ValidationFunc: ValidateMin(10)}, func(cfg *Config, setting *Variable) {
+			var value float64
+			cfg.SetFloat64(&value, setting)
+		}, NewParseErrors(&ValidationError{Name: "RATIO", Value: RedactedValue, Err: &redactedError{msg: "variable 'RATIO' value '******' is not valid", kind: ErrValidation}})},
+		{"sensitive custom validation", &Variable{Name: "TIMEOUT", Sensitive: true, ValidationFunc: func(value interface{}) error {
+			return fmt.Errorf("timeout %v is too long", value)
+		}}, func(cfg *Config, setting *Variable) {
+			var value time.Duration
+			cfg.SetDuration(&value, setting)
+		}, NewParseErrors(&ValidationError{Name: "TIMEOUT", Value: RedactedValue, Err: &redactedError{msg: "variable 'TIMEOUT' value '******' is not valid", kind: ErrValidation}})},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(env)
+		tc.set(cfg, tc.variable)
+		err := cfg.Parse()
+		assert.Equal(t, tc.err, err, tc.name)
+	}
+}
+
+func TestConfigSensitiveErrorChain(t *testing.T) {
+	testcases := []struct {
+		name string
+		f    func(value interface{}) error
+	}{
+		{"quoted value", func(value interface{}) error {
+			return fmt.Errorf("bad %q", value)
+		}},
+		{"value prefix", func(value interface{}) error {
+			return fmt.Errorf("bad %s", value.(string)[:4])
+		}},
+		{"value inside a word", func(value interface{}) error {
+			return fmt.Errorf("got x%sy", value)
+		}},
+		{"wrapped error", func(value interface{}) error {
+			return fmt.Errorf("value '%s': %w", value, errors.New("password is weak"))
+		}},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(&EnvLookuperMock{vars: map[string]string{"DB_PASSWORD": `hun"ter2`}})
+		var password Secret
+		cfg.SetSecret(&password, &Variable{Name: "DB_PASSWORD", ValidationFunc: tc.f})
+		err := cfg.Parse()
+		assert.EqualError(t, err, "config parsing failed: variable 'DB_PASSWORD' value '******' is not valid", fmt.Sprintf("Test case: %s", tc.name))
+		assert.True(t, errors.Is(err, ErrValidation), fmt.Sprintf("Test case: %s", tc.name))
+		var ve *ValidationError
+		assert.True(t, errors.As(err, &ve), fmt.Sprintf("Test case: %s", tc.name))
+		for e := error(ve); e != nil; e = errors.Unwrap(e) {
+			assert.NotContains(t, e.Error(), "hun", fmt.Sprintf("Test case: %s", tc.name))
+		}
+	}
+}
+
+func TestConfigSensitiveTypeError(t *testing.T) {
+	cfg := New()
+	cfg.SetEnvLookuper(&EnvLookuperMock{vars: map[string]string{"PIN": "99999999999999999999"}})
+	var pin int
+	cfg.SetInt(&pin, &Variable{Name: "PIN", Sensitive: true})
+	err := cfg.Parse()
+	assert.EqualError(t, err, "config parsing failed: variable 'PIN' value is out of int range")
+	var te *TypeError
+	assert.True(t, errors.As(err, &te))
+	assert.Equal(t, &redactedError{msg: "value '******' is not int", kind: strconv.ErrRange}, te.Err)
+}
+
+func TestConfigSecret(t *testing.T) {
+	cfg := New()
+	cfg.SetEnvLookuper(&EnvLookuperMock{vars: map[string]string{"DB_PASSWORD": "pa55word"}})
+	var password, token Secret
+	v := &Variable{Name: "DB_PASSWORD"}
+	cfg.SetSecret(&password, v)
+	cfg.SetSecret(&token, &Variable{Name: "API_TOKEN", Default: "t0ken"})
+	assert.Nil(t, cfg.Parse())
+	assert.True(t, v.Sensitive)
+	assert.Equal(t, "pa55word", password.Value())
+	assert.Equal(t, Secret("t0ken"), token)
+
+	fs := newTestFlagSet()
+	cfg.BindFlags(fs)
+	assert.Equal(t, RedactedValue, fs.Lookup("api-token").DefValue)
+}
@@ -0,0 +1,256 @@
+package gocfg
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+)
+
+// ChangeFunc is called by Reload() if the variable value was changed. Old
+// and new values have the go type of the variable.
+type ChangeFunc func(old, new interface{})
+
+// Reloader is implemented by EnvLookupers which can re-read their values,
+// like DotEnvLookuper and FileLookuper. Reload() of Config parses variables
+// with the new values and commits them only if parsing succeeded.
+type Reloader interface {
+	// Reload re-reads values without changing the current ones. It returns
+	// EnvLookuper of the new values and func which makes them current.
+	Reload() (next EnvLookuper, commit func(), err error)
+}
+
+// change is a changed value of the variable.
+type change struct {
+	name     string
+	old, new interface{}
+}
+
+// OnChange adds a func which is called by Reload() if the variable value was
+// changed. Funcs are called after all values were assigned, in the order
+// variables were defined, so they could read other variables.
+func (c *Config) OnChange(name string, f ChangeFunc) {
+	formatEnvVarName(&name)
+	if c.onChange == nil {
+		c.onChange = map[string][]ChangeFunc{}
+	}
+	c.onChange[name] = append(c.onChange[name], f)
+}
+
+// OnReloadError sets a func which is called by Watch() if reload failed.
+func (c *Config) OnReloadError(f func(err error)) {
+	c.onReloadError = f
+}
+
+// Reload reloads Reloader sources, then lookups for defined variables and
+// validates them like Parse(). Reload is transactional: variables are parsed
+// with new values of sources into new values, which are assigned and
+// committed to sources only if all sources, variables and rules are valid.
+// Otherwise the error is returned and nothing is changed. OnChange funcs are
+// called for changed variables after values were assigned.
+//
+// Values are assigned while the config is locked, so other goroutines
+// should read pointers inside View().
+func (c *Config) Reload() error {
+	c.reloadMu.Lock()
+	changes, err := c.reload()
+	c.reloadMu.Unlock()
+	if err != nil {
+		return err
+	}
+	for _, ch := range changes {
+		for _, f := range c.onChange[ch.name] {
+			f(ch.old, ch.new)
+		}
+	}
+	return nil
+}
+
+// reload parses variables into new values and assigns them if all of them
+// are valid. It returns changed values.
+func (c *Config) reload() ([]change, error) {
+	sources := make([]*source, len(c.sources))
+	var commits []func()
+	for i, s := range c.sources {
+		sources[i] = s
+		if r, ok := s.lookuper.(Reloader); ok {
+			next, commit, err := r.Reload()
+			if err != nil {
+				return nil, err
+			}
+			sources[i] = &source{name: s.name, lookuper: next, priority: s.priority}
+			commits = append(commits, commit)
+		}
+	}
+	staged := &Config{
+		variables:   make([]*Variable, len(c.variables)),
+		rules:       c.rules,
+		sources:     sources,
+		flags:       c.flags,
+		args:        c.args,
+		maxFileSize: c.maxFileSize,
+	}
+	for i, v := range c.variables {
+		sv := *v
+		sv.pointer = reflect.New(reflect.TypeOf(v.pointer).Elem()).Interface()
+		staged.variables[i] = &sv
+	}
+	if err := staged.parse(); err != nil {
+		return nil, err
+	}
+	for _, commit := range commits {
+		commit()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var changes []change
+	for i, v := range c.variables {
+		sv := staged.variables[i]
+		p := reflect.ValueOf(v.pointer).Elem()
+		value := reflect.ValueOf(sv.pointer).Elem()
+		if old := p.Interface(); !reflect.DeepEqual(old, value.Interface()) {
+			changes = append(changes, change{name: v.Name, old: old, new: value.Interface()})
+		}
+		p.Set(value)
+		v.source, v.raw, v.value, v.failed = sv.source, sv.raw, sv.value, sv.failed
+		if v.store != nil {
+			v.store()
+		}
+	}
+	return changes, nil
+}
+
+// View calls f while the config is locked for reading, so values read by f
+// are not changed by concurrent Reload(). f should not call Config methods.
+func (c *Config) View(f func()) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	f()
+}
+
+// Watch calls Reload() on every trigger event until ctx is done or trigger
+// is closed. Reload errors are passed to the func set by OnReloadError. It
+// returns ctx error if ctx is done.
+//
+// Example of reloading on SIGHUP:
+//
+//	go cfg.Watch(ctx, gocfg.SignalTrigger(ctx))
+func (c *Config) Watch(ctx context.Context, trigger <-chan struct{}) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-trigger:
+			if !ok {
+				return nil
+			}
+			if err := c.Reload(); err != nil && c.onReloadError != nil {
+				c.onReloadError(err)
+			}
+		}
+	}
+}
+
+// notify sends the trigger event unless the previous one is pending.
+func notify(trigger chan struct{}) {
+	select {
+	case trigger <- struct{}{}:
+	default:
+	}
+}
+
+// SignalTrigger returns a trigger which fires when the process receives any
+// of the signals, SIGHUP by default. It's closed when ctx is done.
+func SignalTrigger(ctx context.Context, sigs ...os.Signal) <-chan struct{} {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	trigger := make(chan struct{}, 1)
+	go func() {
+		defer close(trigger)
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ch:
+				notify(trigger)
+			}
+		}
+	}()
+	return trigger
+}
+
+// TickerTrigger returns a trigger which fires every interval. It's closed
+// when ctx is done.
+func TickerTrigger(ctx context.Context, interval time.Duration) <-chan struct{} {
+	trigger := make(chan struct{}, 1)
+	go func() {
+		defer close(trigger)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				notify(trigger)
+			}
+		}
+	}()
+	return trigger
+}
+
+// fileState is a file modification time and size, it's zero if the file
+// doesn't exist.
+type fileState struct {
+	modTime int64
+	size    int64
+}
+
+// statFile returns the file state.
+func statFile(path string) fileState {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{modTime: fi.ModTime().UnixNano(), size: fi.Size()}
+}
+
+// FileTrigger returns a trigger which fires when any of the files is
+// created, changed or removed, e.g. .env or config file read by a Reloader
+// source. Files are checked every interval. It's closed when ctx is done.
+func FileTrigger(ctx context.Context, interval time.Duration, paths ...string) <-chan struct{} {
+	states := make([]fileState, len(paths))
+	for i, path := range paths {
+		states[i] = statFile(path)
+	}
+	trigger := make(chan struct{}, 1)
+	go func() {
+		defer close(trigger)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				changed := false
+				for i, path := range paths {
+					if s := statFile(path); s != states[i] {
+						states[i] = s
+						changed = true
+					}
+				}
+				if changed {
+					notify(trigger)
+				}
+			}
+		}
+	}()
+	return trigger
+}
@@ -0,0 +1,236 @@
+package gocfg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigReload(t *testing.T) {
+	env := &EnvLookuperMock{vars: map[string]string{
+		"LOG_LEVEL":  "INFO",
+		"RATE_LIMIT": "100",
+	}}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	var logLevel, region string
+	var rateLimit int
+	cfg.SetString(&logLevel, &Variable{Name: "LOG_LEVEL"})
+	cfg.SetInt(&rateLimit, &Variable{Name: "RATE_LIMIT", ValidationFunc: ValidateBetween(1, 1000)})
+	cfg.SetString(&region, &Variable{Name: "REGION", Default: "eu"})
+	var changes []string
+	for _, name := range []string{"LOG_LEVEL", "RATE_LIMIT", "REGION"} {
+		name := name
+		cfg.OnChange(name, func(old, new interface{}) {
+			changes = append(changes, name)
+			switch name {
+			case "LOG_LEVEL":
+				assert.Equal(t, "INFO", old)
+				assert.Equal(t, "DEBUG", new)
+			case "RATE_LIMIT":
+				assert.Equal(t, 100, old)
+				assert.Equal(t, 500, new)
+			}
+		})
+	}
+	assert.Nil(t, cfg.Parse())
+	assert.Nil(t, changes)
+
+	env.vars = map[string]string{"LOG_LEVEL": "DEBUG", "RATE_LIMIT": "500"}
+	assert.Nil(t, cfg.Reload())
+	assert.Equal(t, []string{"LOG_LEVEL", "RATE_LIMIT"}, changes)
+	assert.Equal(t, "DEBUG", logLevel)
+	assert.Equal(t, 500, rateLimit)
+	assert.Equal(t, "eu", region)
+
+	// values are not changed if any variable fails
+	changes = nil
+	env.vars = map[string]string{"LOG_LEVEL": "WARN", "RATE_LIMIT": "5000", "REGION": "us"}
+	err := cfg.Reload()
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Nil(t, changes)
+	assert.Equal(t, "DEBUG", logLevel)
+	assert.Equal(t, 500, rateLimit)
+	assert.Equal(t, "eu", region)
+	source, _ := cfg.Source("REGION")
+	assert.Equal(t, SourceDefault, source)
+}
+
+func TestConfigReloadRules(t *testing.T) {
+	env := &EnvLookuperMock{vars: map[string]string{"MIN_POOL": "1", "MAX_POOL": "10"}}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	var minPool, maxPool int
+	cfg.SetInt(&minPool, &Variable{Name: "MIN_POOL"})
+	cfg.SetInt(&maxPool, &Variable{Name: "MAX_POOL"})
+	cfg.AddRule(func(values map[string]interface{}) error {
+		if values["MIN_POOL"].(int) > values["MAX_POOL"].(int) {
+			return errors.New("min pool is greater than max pool")
+		}
+		return nil
+	}, "MIN_POOL", "MAX_POOL")
+	assert.Nil(t, cfg.Parse())
+
+	env.vars = map[string]string{"MIN_POOL": "20", "MAX_POOL": "10"}
+	assert.True(t, errors.Is(cfg.Reload(), ErrRule))
+	assert.Equal(t, 1, minPool)
+	assert.Equal(t, 10, maxPool)
+}
+
+func TestConfigReloadDotEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	assert.Nil(t, os.WriteFile(path, []byte("LOG_LEVEL=INFO\n"), 0o600))
+	dotenv, err := NewDotEnvLookuper(path)
+	assert.Nil(t, err)
+	cfg := New()
+	cfg.SetEnvLookuper(&EnvLookuperMock{})
+	cfg.AddSource(".env", dotenv, -1)
+	var logLevel string
+	cfg.SetString(&logLevel, &Variable{Name: "LOG_LEVEL"})
+	assert.Nil(t, cfg.Parse())
+	assert.Equal(t, "INFO", logLevel)
+
+	assert.Nil(t, os.WriteFile(path, []byte("LOG_LEVEL=DEBUG\n"), 0o600))
+	assert.Nil(t, cfg.Reload())
+	assert.Equal(t, "DEBUG", logLevel)
+
+	// malformed file is not reloaded
+	assert.Nil(t, os.WriteFile(path, []byte("LOG_LEVEL='WARN\n"), 0o600))
+	assert.NotNil(t, cfg.Reload())
+	assert.Equal(t, "DEBUG", logLevel)
+	v, _ := dotenv.LookupEnv("LOG_LEVEL")
+	assert.Equal(t, "DEBUG", v)
+}
+
+func TestConfigReloadSourcesTransactional(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	jsonPath := filepath.Join(dir, "config.json")
+	assert.Nil(t, os.WriteFile(envPath, []byte("LOG_LEVEL=INFO\n"), 0o600))
+	assert.Nil(t, os.WriteFile(jsonPath, []byte(`{"workers": 4}`), 0o600))
+	dotenv, err := NewDotEnvLookuper(envPath)
+	assert.Nil(t, err)
+	file, err := NewFileLookuper(jsonPath)
+	assert.Nil(t, err)
+	cfg := New()
+	cfg.SetEnvLookuper(&EnvLookuperMock{})
+	cfg.AddSource(".env", dotenv, -1)
+	cfg.AddSource("config.json", file, -2)
+	var logLevel string
+	var workers int
+	cfg.SetString(&logLevel, &Variable{Name: "LOG_LEVEL"})
+	cfg.SetInt(&workers, &Variable{Name: "WORKERS", ValidationFunc: ValidateBetween(1, 16)})
+	assert.Nil(t, cfg.Parse())
+
+	// the new .env is valid, but the config file value is not
+	assert.Nil(t, os.WriteFile(envPath, []byte("LOG_LEVEL=DEBUG\n"), 0o600))
+	assert.Nil(t, os.WriteFile(jsonPath, []byte(`{"workers": 64}`), 0o600))
+	assert.True(t, errors.Is(cfg.Reload(), ErrValidation))
+	assert.Equal(t, "INFO", logLevel)
+	v, _ := dotenv.LookupEnv("LOG_LEVEL")
+	assert.Equal(t, "INFO", v)
+	v, _ = file.LookupEnv("WORKERS")
+	assert.Equal(t, "4", v)
+
+	assert.Nil(t, os.WriteFile(jsonPath, []byte(`{"workers": 8}`), 0o600))
+	assert.Nil(t, cfg.Reload())
+	assert.Equal(t, "DEBUG", logLevel)
+	assert.Equal(t, 8, workers)
+	v, _ = dotenv.LookupEnv("LOG_LEVEL")
+	assert.Equal(t, "DEBUG", v)
+}
+
+func TestConfigWatch(t *testing.T) {
+	env := &EnvLookuperMock{vars: map[string]string{"RATE_LIMIT": "100"}}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	var rateLimit int
+	cfg.SetInt(&rateLimit, &Variable{Name: "RATE_LIMIT", ValidationFunc: ValidateBetween(1, 1000)})
+	changed := make(chan interface{}, 1)
+	cfg.OnChange("RATE_LIMIT", func(old, new interface{}) { changed <- new })
+	failed := make(chan error, 1)
+	cfg.OnReloadError(func(err error) { failed <- err })
+	assert.Nil(t, cfg.Parse())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	trigger := make(chan struct{})
+	done := make(chan error)
+	go func() { done <- cfg.Watch(ctx, trigger) }()
+
+	env.vars = map[string]string{"RATE_LIMIT": "200"}
+	trigger <- struct{}{}
+	assert.Equal(t, 200, <-changed)
+	cfg.View(func() {
+		assert.Equal(t, 200, rateLimit)
+	})
+
+	env.vars = map[string]string{"RATE_LIMIT": "0"}
+	trigger <- struct{}{}
+	assert.True(t, errors.Is(<-failed, ErrValidation))
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
+
+func TestFileTrigger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	assert.Nil(t, os.WriteFile(path, []byte("LOG_LEVEL=INFO\n"), 0o600))
+	ctx, cancel := context.WithCancel(context.Background())
+	trigger := FileTrigger(ctx, 10*time.Millisecond, path)
+	assert.Nil(t, os.WriteFile(path, []byte("LOG_LEVEL=DEBUG\n"), 0o600))
+	select {
+	case <-trigger:
+	case <-time.After(time.Second):
+		t.Fatal("file change was not detected")
+	}
+	cancel()
+	for range trigger {
+	}
+}
+
+func TestLookupersConcurrentReload(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	jsonPath := filepath.Join(dir, "config.json")
+	assert.Nil(t, os.WriteFile(envPath, []byte("LOG_LEVEL=INFO\n"), 0o600))
+	assert.Nil(t, os.WriteFile(jsonPath, []byte(`{"log_level": "INFO"}`), 0o600))
+	dotenv, err := NewDotEnvLookuper(envPath)
+	assert.Nil(t, err)
+	file, err := NewFileLookuper(jsonPath)
+	assert.Nil(t, err)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, l := range []EnvLookuper{dotenv, file} {
+		wg.Add(1)
+		go func(l EnvLookuper) {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if _, ok := l.LookupEnv("LOG_LEVEL"); !ok {
+					t.Error("LOG_LEVEL is not found")
+					return
+				}
+			}
+		}(l)
+	}
+	for i := 0; i < 50; i++ {
+		for _, r := range []Reloader{dotenv, file} {
+			_, commit, err := r.Reload()
+			assert.Nil(t, err)
+			commit()
+		}
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,174 @@
+package gocfg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RuleFunc validates values of several variables. The values map holds
+// values of the rule variables by their names, variables which were not set
+// and have no default value are missing in the map.
+type RuleFunc func(values map[string]interface{}) error
+
+// rule is a validation of several variables.
+type rule struct {
+	names []string
+	check RuleFunc
+	// custom errors are prefixed with the names of variables
+	custom bool
+}
+
+// AddRule adds a rule which validates several variables after all variables
+// are parsed. The rule is skipped if any of its variables failed to parse.
+// Errors are prefixed with the names of variables, e.g.
+//
+//	cfg.AddRule(func(values map[string]interface{}) error {
+//		if values["MIN_POOL"].(int) > values["MAX_POOL"].(int) {
+//			return errors.New("min pool is greater than max pool")
+//		}
+//		return nil
+//	}, "MIN_POOL", "MAX_POOL")
+//
+// fails with "variables 'MIN_POOL', 'MAX_POOL': min pool is greater than
+// max pool".
+func (c *Config) AddRule(f RuleFunc, names ...string) {
+	c.addRule(f, true, formatNames(names))
+}
+
+// RequiredIf adds a rule which requires variables if the variable name has
+// the value, e.g. TLS_CERT and TLS_KEY are required if TLS_ENABLED is true.
+// The value is compared with the parsed variable value, so it should have
+// the variable type.
+func (c *Config) RequiredIf(name string, value interface{}, required ...string) {
+	names := formatNames(append([]string{name}, required...))
+	name = names[0]
+	c.addRule(func(values map[string]interface{}) error {
+		v, ok := values[name]
+		if !ok || !reflect.DeepEqual(v, value) {
+			return nil
+		}
+		var errs ValidationErrors
+		for _, r := range names[1:] {
+			if _, ok := values[r]; !ok {
+				errs.add(fmt.Errorf("'%s' variable is required if '%s' is '%v'", r, name, value))
+			}
+		}
+		return errs.err()
+	}, false, names)
+}
+
+// MutuallyExclusive adds a rule which allows to set at most one of the
+// variables.
+func (c *Config) MutuallyExclusive(names ...string) {
+	names = formatNames(names)
+	c.addRule(func(values map[string]interface{}) error {
+		if set := setNames(values, names); len(set) > 1 {
+			return fmt.Errorf("variables %s are mutually exclusive", quoteNames(set))
+		}
+		return nil
+	}, false, names)
+}
+
+// OneOf adds a rule which requires to set exactly one of the variables.
+func (c *Config) OneOf(names ...string) {
+	names = formatNames(names)
+	c.addRule(func(values map[string]interface{}) error {
+		set := setNames(values, names)
+		switch {
+		case len(set) == 0:
+			return fmt.Errorf("one of variables %s is required", quoteNames(names))
+		case len(set) > 1:
+			return fmt.Errorf("only one of variables %s could be set", quoteNames(set))
+		}
+		return nil
+	}, false, names)
+}
+
+// addRule adds rule to config.
+func (c *Config) addRule(f RuleFunc, custom bool, names []string) {
+	c.rules = append(c.rules, &rule{names: names, check: f, custom: custom})
+}
+
+// checkRule runs the rule with values of it's variables. Errors of rules
+// with sensitive variables are replaced, since they could contain values.
+func (c *Config) checkRule(r *rule) error {
+	values := make(map[string]interface{}, len(r.names))
+	sensitive := false
+	for _, name := range r.names {
+		v := c.variable(name)
+		if v == nil {
+			return &RuleError{Names: r.names, Err: fmt.Errorf("rule for variables %s uses undefined variable '%s'", quoteNames(r.names), name)}
+		}
+		if v.failed {
+			return nil
+		}
+		if v.value != nil {
+			values[name] = v.value
+		}
+		if v.Sensitive {
+			sensitive = true
+		}
+	}
+	err := r.check(values)
+	if err == nil {
+		return nil
+	}
+	if sensitive {
+		// the rule error could contain sensitive values
+		err = redactError(err, ErrRule, "rule is violated by sensitive values")
+	}
+	return newRuleError(r, err)
+}
+
+// newRuleError returns RuleError for the rule error or ValidationErrors of
+// RuleError for multiple errors.
+func newRuleError(r *rule, err error) error {
+	if errs, ok := err.(ValidationErrors); ok {
+		wrapped := make(ValidationErrors, len(errs))
+		for i, e := range errs {
+			wrapped[i] = newRuleError(r, e)
+		}
+		return wrapped
+	}
+	return &RuleError{Names: r.names, Err: err, custom: r.custom}
+}
+
+// variable returns the variable by name or nil if it's not defined.
+func (c *Config) variable(name string) *Variable {
+	for _, v := range c.variables {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// formatNames returns a copy of names formatted as variable names.
+func formatNames(names []string) []string {
+	formatted := append([]string{}, names...)
+	for i := range formatted {
+		formatEnvVarName(&formatted[i])
+	}
+	return formatted
+}
+
+// setNames returns names of variables which have values.
+func setNames(values map[string]interface{}, names []string) []string {
+	var set []string
+	for _, name := range names {
+		if _, ok := values[name]; ok {
+			set = append(set, name)
+		}
+	}
+	return set
+}
+
+// quoteNames returns quoted names separated by comma.
+func quoteNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
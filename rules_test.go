@@ -0,0 +1,105 @@
+package gocfg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigRules(t *testing.T) {
+	poolRule := func(values map[string]interface{}) error {
+		if values["MIN_POOL"].(int) > values["MAX_POOL"].(int) {
+			return errors.New("min pool is greater than max pool")
+		}
+		return nil
+	}
+
+	testcases := []struct {
+		name  string
+		vars  map[string]string
+		rules func(cfg *Config)
+		err   error
+	}{
+		{"required if", map[string]string{"TLS_ENABLED": "true", "TLS_CERT": "cert.pem", "TLS_KEY": "key.pem"}, func(cfg *Config) {
+			cfg.RequiredIf("TLS_ENABLED", true, "TLS_CERT", "TLS_KEY")
+		}, nil},
+		{"required if condition is false", map[string]string{"TLS_ENABLED": "false"}, func(cfg *Config) {
+			cfg.RequiredIf("TLS_ENABLED", true, "TLS_CERT", "TLS_KEY")
+		}, nil},
+		{"required if failed", map[string]string{"TLS_ENABLED": "true", "TLS_CERT": "cert.pem"}, func(cfg *Config) {
+			cfg.RequiredIf("TLS_ENABLED", true, "TLS_CERT", "TLS_KEY")
+		}, NewParseErrors(&RuleError{Names: []string{"TLS_ENABLED", "TLS_CERT", "TLS_KEY"}, Err: errors.New("'TLS_KEY' variable is required if 'TLS_ENABLED' is 'true'")})},
+		{"required if all failed", map[string]string{"TLS_ENABLED": "true"}, func(cfg *Config) {
+			cfg.RequiredIf("tls_enabled", true, "tls_cert", "tls_key")
+		}, NewParseErrors(
+			&RuleError{Names: []string{"TLS_ENABLED", "TLS_CERT", "TLS_KEY"}, Err: errors.New("'TLS_CERT' variable is required if 'TLS_ENABLED' is 'true'")},
+			&RuleError{Names: []string{"TLS_ENABLED", "TLS_CERT", "TLS_KEY"}, Err: errors.New("'TLS_KEY' variable is required if 'TLS_ENABLED' is 'true'")},
+		)},
+		{"mutually exclusive", map[string]string{"S3_BUCKET": "backups"}, func(cfg *Config) {
+			cfg.MutuallyExclusive("S3_BUCKET", "GCS_BUCKET")
+		}, nil},
+		{"mutually exclusive failed", map[string]string{"S3_BUCKET": "backups", "GCS_BUCKET": "backups"}, func(cfg *Config) {
+			cfg.MutuallyExclusive("S3_BUCKET", "GCS_BUCKET")
+		}, NewParseErrors(&RuleError{Names: []string{"S3_BUCKET", "GCS_BUCKET"}, Err: errors.New("variables 'S3_BUCKET', 'GCS_BUCKET' are mutually exclusive")})},
+		{"one of", map[string]string{"GCS_BUCKET": "backups"}, func(cfg *Config) {
+			cfg.OneOf("S3_BUCKET", "GCS_BUCKET")
+		}, nil},
+		{"one of none is set", map[string]string{}, func(cfg *Config) {
+			cfg.OneOf("S3_BUCKET", "GCS_BUCKET")
+		}, NewParseErrors(&RuleError{Names: []string{"S3_BUCKET", "GCS_BUCKET"}, Err: errors.New("one of variables 'S3_BUCKET', 'GCS_BUCKET' is required")})},
+		{"one of both are set", map[string]string{"S3_BUCKET": "backups", "GCS_BUCKET": "backups"}, func(cfg *Config) {
+			cfg.OneOf("S3_BUCKET", "GCS_BUCKET")
+		}, NewParseErrors(&RuleError{Names: []string{"S3_BUCKET", "GCS_BUCKET"}, Err: errors.New("only one of variables 'S3_BUCKET', 'GCS_BUCKET' could be set")})},
+		{"custom rule", map[string]string{"MIN_POOL": "2"}, func(cfg *Config) {
+			cfg.AddRule(poolRule, "MIN_POOL", "MAX_POOL")
+		}, nil},
+		{"custom rule failed", map[string]string{"MIN_POOL": "20"}, func(cfg *Config) {
+			cfg.AddRule(poolRule, "MIN_POOL", "MAX_POOL")
+		}, NewParseErrors(&RuleError{Names: []string{"MIN_POOL", "MAX_POOL"}, Err: errors.New("min pool is greater than max pool"), custom: true})},
+		{"custom rule skipped on parsing error", map[string]string{"MIN_POOL": "many"}, func(cfg *Config) {
+			cfg.AddRule(poolRule, "MIN_POOL", "MAX_POOL")
+		}, NewParseErrors(&TypeError{Name: "MIN_POOL", Value: "many", Type: "int", Err: strconv.ErrSyntax})},
+		{"undefined variable", map[string]string{}, func(cfg *Config) {
+			cfg.MutuallyExclusive("S3_BUCKET", "AZURE_CONTAINER")
+		}, NewParseErrors(&RuleError{Names: []string{"S3_BUCKET", "AZURE_CONTAINER"}, Err: errors.New("rule for variables 'S3_BUCKET', 'AZURE_CONTAINER' uses undefined variable 'AZURE_CONTAINER'")})},
+	}
+
+	for _, tc := range testcases {
+		cfg := New()
+		cfg.SetEnvLookuper(&EnvLookuperMock{vars: tc.vars})
+		var tlsEnabled bool
+		var tlsCert, tlsKey, s3Bucket, gcsBucket string
+		var minPool int
+		maxPool := 0
+		cfg.SetBool(&tlsEnabled, &Variable{Name: "TLS_ENABLED", Default: false})
+		cfg.SetString(&tlsCert, &Variable{Name: "TLS_CERT"})
+		cfg.SetString(&tlsKey, &Variable{Name: "TLS_KEY"})
+		cfg.SetString(&s3Bucket, &Variable{Name: "S3_BUCKET"})
+		cfg.SetString(&gcsBucket, &Variable{Name: "GCS_BUCKET"})
+		cfg.SetInt(&minPool, &Variable{Name: "MIN_POOL", Default: 1})
+		cfg.SetInt(&maxPool, &Variable{Name: "MAX_POOL", Default: 10})
+		tc.rules(cfg)
+		err := cfg.Parse()
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s, Error: '%+v'", tc.name, err))
+	}
+}
+
+func TestConfigRulesRedaction(t *testing.T) {
+	cfg := New()
+	cfg.SetEnvLookuper(&EnvLookuperMock{vars: map[string]string{"DB_PASSWORD": "s3cr3t", "DB_USER": "s3cr3t"}})
+	var password Secret
+	var user string
+	cfg.SetSecret(&password, &Variable{Name: "DB_PASSWORD"})
+	cfg.SetString(&user, &Variable{Name: "DB_USER"})
+	cfg.AddRule(func(values map[string]interface{}) error {
+		if values["DB_PASSWORD"].(Secret).Value() == values["DB_USER"] {
+			return fmt.Errorf("password '%s' equals to user name", values["DB_PASSWORD"].(Secret).Value())
+		}
+		return nil
+	}, "DB_PASSWORD", "DB_USER")
+	err := cfg.Parse()
+	assert.Equal(t, NewParseErrors(&RuleError{Names: []string{"DB_PASSWORD", "DB_USER"}, Err: &redactedError{msg: "rule is violated by sensitive values", kind: ErrRule}, custom: true}), err)
+}
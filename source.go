@@ -0,0 +1,98 @@
+package gocfg
+
+import "sort"
+
+// Names of the predefined sources.
+const (
+	// SourceEnv is the process environment, or EnvLookuper set by
+	// SetEnvLookuper.
+	SourceEnv = "env"
+	// SourceDefault is the variable default value.
+	SourceDefault = "default"
+	// SourceFlag is the command-line flags source added by BindFlags.
+	SourceFlag = "flag"
+)
+
+// source is a named EnvLookuper with priority.
+type source struct {
+	name     string
+	lookuper EnvLookuper
+	priority int
+}
+
+// AddSource adds EnvLookuper to the chain of sources. Variables are looked up
+// in sources with higher priority first, sources with equal priority are
+// looked up in the order they were added. The process environment is the
+// SourceEnv source with priority 0. Adding a source with an existing name
+// replaces it.
+//
+// Example of "env > .env.local > .env > defaults" chain:
+//
+//	local, _ := gocfg.NewDotEnvLookuper(".env.local")
+//	dotenv, _ := gocfg.NewDotEnvLookuper(".env")
+//	cfg.AddSource(".env.local", local, -1)
+//	cfg.AddSource(".env", dotenv, -2)
+func (c *Config) AddSource(name string, l EnvLookuper, priority int) {
+	s := &source{name: name, lookuper: l, priority: priority}
+	replaced := false
+	for i, cs := range c.sources {
+		if cs.name == name {
+			c.sources[i] = s
+			replaced = true
+		}
+	}
+	if !replaced {
+		c.sources = append(c.sources, s)
+	}
+	sort.SliceStable(c.sources, func(i, j int) bool {
+		return c.sources[i].priority > c.sources[j].priority
+	})
+}
+
+// Source returns the name of the source which supplied the variable value
+// on the last Parse(). It's SourceDefault if the default value was used and
+// empty string if the value was not found. It returns false if the variable
+// is not defined.
+func (c *Config) Source(name string) (string, bool) {
+	formatEnvVarName(&name)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if v := c.variable(name); v != nil {
+		return v.source, true
+	}
+	return "", false
+}
+
+// lookup lookups for the variable in the chain of sources and records the
+// source which supplied it. Variables with AllowFile are read from the file
+// referenced by NAME_FILE variable if NAME is not defined.
+func (c *Config) lookup(setting *Variable) (string, bool, error) {
+	v, ok, err := c.lookupSources(setting)
+	if err != nil || ok || !setting.AllowFile {
+		return v, ok, err
+	}
+	return c.lookupFile(setting)
+}
+
+// lookupSources lookups for the variable in the chain of sources.
+func (c *Config) lookupSources(setting *Variable) (string, bool, error) {
+	setting.source = ""
+	for _, s := range c.sources {
+		var v string
+		var ok bool
+		var err error
+		if l, isErrorLookuper := s.lookuper.(EnvLookuperWithError); isErrorLookuper {
+			v, ok, err = l.LookupEnvWithError(setting.Name)
+		} else {
+			v, ok = s.lookuper.LookupEnv(setting.Name)
+		}
+		if err != nil {
+			return "", false, err
+		}
+		if ok {
+			setting.source = s.name
+			return v, true, nil
+		}
+	}
+	return "", false, nil
+}
@@ -0,0 +1,68 @@
+package gocfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigSources(t *testing.T) {
+	env := &EnvLookuperMock{
+		vars: map[string]string{
+			"LOG_LEVEL": "DEBUG",
+		},
+	}
+	local := &EnvLookuperMock{
+		vars: map[string]string{
+			"LOG_LEVEL": "INFO",
+			"API_URL":   "https://local.example.com",
+		},
+	}
+	dotenv := &EnvLookuperMock{
+		vars: map[string]string{
+			"API_URL":     "https://api.example.com",
+			"API_VERSION": "v1",
+		},
+	}
+	cfg := New()
+	cfg.SetEnvLookuper(env)
+	cfg.AddSource(".env", dotenv, -2)
+	cfg.AddSource(".env.local", local, -1)
+	var logLevel, apiURL, apiVersion, logFormat, region string
+	cfg.SetString(&logLevel, &Variable{Name: "LOG_LEVEL"})
+	cfg.SetString(&apiURL, &Variable{Name: "API_URL"})
+	cfg.SetString(&apiVersion, &Variable{Name: "API_VERSION"})
+	cfg.SetString(&logFormat, &Variable{Name: "LOG_FORMAT", Default: "JSON"})
+	cfg.SetString(&region, &Variable{Name: "REGION"})
+	assert.Nil(t, cfg.Parse())
+
+	assert.Equal(t, "DEBUG", logLevel)
+	assert.Equal(t, "https://local.example.com", apiURL)
+	assert.Equal(t, "v1", apiVersion)
+	assert.Equal(t, "JSON", logFormat)
+
+	testcases := []struct {
+		name    string
+		source  string
+		defined bool
+	}{
+		{"LOG_LEVEL", SourceEnv, true},
+		{"api-url", ".env.local", true},
+		{"API_VERSION", ".env", true},
+		{"LOG_FORMAT", SourceDefault, true},
+		{"REGION", "", true},
+		{"UNDEFINED", "", false},
+	}
+	for _, tc := range testcases {
+		source, ok := cfg.Source(tc.name)
+		assert.Equal(t, tc.source, source, tc.name)
+		assert.Equal(t, tc.defined, ok, tc.name)
+	}
+
+	// source with the same name is replaced
+	cfg.AddSource(".env", &EnvLookuperMock{vars: map[string]string{"API_VERSION": "v2"}}, 1)
+	assert.Nil(t, cfg.Parse())
+	assert.Equal(t, "v2", apiVersion)
+	source, _ := cfg.Source("API_VERSION")
+	assert.Equal(t, ".env", source)
+}
@@ -0,0 +1,208 @@
+package gocfg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// HelpEnv is the environment variable which enables help output of Parse()
+// if help was enabled by EnableHelp.
+const HelpEnv = "GOCFG_HELP"
+
+// ErrHelp is returned by Parse() if help was requested.
+var ErrHelp = errors.New("gocfg: help requested")
+
+// validatorInfo describes validation funcs: their descriptions and JSON
+// Schema keywords.
+type validatorInfo struct {
+	descs    []string
+	keywords map[string]interface{}
+	// partial is true if some of validation funcs have no keywords
+	partial bool
+}
+
+// add adds description and JSON Schema keywords of the validation func.
+// Keywords are nil if the validation func can't be expressed in JSON Schema.
+func (p *validatorInfo) add(desc string, keywords map[string]interface{}) {
+	p.descs = append(p.descs, desc)
+	if keywords == nil {
+		p.partial = true
+		return
+	}
+	if p.keywords == nil {
+		p.keywords = map[string]interface{}{}
+	}
+	mergeKeywords(p.keywords, keywords)
+}
+
+// mergeKeywords adds JSON Schema keywords of src to dst. Keywords which are
+// already defined in dst are added to "allOf".
+func mergeKeywords(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if _, ok := dst[k]; !ok {
+			dst[k] = v
+			continue
+		}
+		allOf, _ := dst["allOf"].([]interface{})
+		if k == "allOf" {
+			dst["allOf"] = append(allOf, v.([]interface{})...)
+			continue
+		}
+		dst["allOf"] = append(allOf, map[string]interface{}{k: v})
+	}
+}
+
+// describedFunc is a validation func with the description.
+type describedFunc struct {
+	f    func(value interface{}) error
+	info *validatorInfo
+}
+
+// describeRequest is passed to described validation funcs to get their
+// description.
+type describeRequest struct {
+	info *validatorInfo
+}
+
+// validate calls the validation func. If value is describeRequest, it sets
+// the description instead.
+func (d *describedFunc) validate(value interface{}) error {
+	if r, ok := value.(*describeRequest); ok {
+		r.info = d.info
+		return nil
+	}
+	return d.f(value)
+}
+
+// describedCode is the code pointer of describedFunc method values, it's
+// the same for all of them.
+var describedCode = reflect.ValueOf((&describedFunc{}).validate).Pointer()
+
+// withInfo returns validation func f which carries the description.
+func withInfo(info *validatorInfo, f func(value interface{}) error) func(value interface{}) error {
+	return (&describedFunc{f: f, info: info}).validate
+}
+
+// lookupValidator returns the description of validation func f. Funcs
+// which were not described, like custom ones, are described as "custom".
+// Only described funcs are asked for the description, other funcs are
+// never called to describe them.
+func lookupValidator(f func(value interface{}) error) *validatorInfo {
+	if f != nil && reflect.ValueOf(f).Pointer() == describedCode {
+		r := &describeRequest{}
+		f(r)
+		return r.info
+	}
+	return &validatorInfo{descs: []string{"custom"}, partial: true}
+}
+
+// Describe returns validation func f with the description shown in usage,
+// generated docs and examples. Keywords are JSON Schema keywords added by
+// ExportJSONSchema, they are nil if f can't be expressed in JSON Schema.
+// Validation funcs without descriptions are shown as "custom".
+func Describe(desc string, keywords map[string]interface{}, f func(value interface{}) error) func(value interface{}) error {
+	info := &validatorInfo{}
+	info.add(desc, keywords)
+	return withInfo(info, f)
+}
+
+// describeValidator returns descriptions of the validation func.
+func describeValidator(f func(value interface{}) error) []string {
+	return lookupValidator(f).descs
+}
+
+// validationSummary returns descriptions of all variable validation funcs.
+// Descriptions of ElementValidationFunc are prefixed with "each".
+func validationSummary(v *Variable) []string {
+	var descs []string
+	if v.ElementValidationFunc != nil {
+		for _, d := range describeValidator(v.ElementValidationFunc) {
+			descs = append(descs, "each "+d)
+		}
+	}
+	if v.ValidationFunc != nil {
+		descs = append(descs, describeValidator(v.ValidationFunc)...)
+	}
+	for _, f := range v.Validators {
+		descs = append(descs, describeValidator(f)...)
+	}
+	return descs
+}
+
+// typeName returns the variable type name. Types of SLICE and MAP variables
+// include the element type, e.g. []int or map[string]int.
+func typeName(v *Variable) string {
+	switch v.valueType {
+	case SLICE:
+		return "[]" + v.elemType.String()
+	case MAP:
+		return "map[string]" + v.elemType.String()
+	}
+	return v.valueType.String()
+}
+
+// defaultString returns the variable default value formatted like the
+// environment variable value. It's masked if the variable is sensitive.
+func defaultString(v *Variable) string {
+	if v.Default == nil {
+		return ""
+	}
+	return displayValue(v, formatValue(v, v.Default))
+}
+
+// Usage writes a table of all defined variables with their types, required
+// flags, default values, descriptions and validation summaries.
+func (c *Config) Usage(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tREQUIRED\tDEFAULT\tDESCRIPTION\tVALIDATION")
+	for _, v := range c.variables {
+		required := ""
+		if v.Required {
+			required = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", v.Name, typeName(v), required, defaultString(v), v.Description, strings.Join(validationSummary(v), ", "))
+	}
+	return tw.Flush()
+}
+
+// EnableHelp makes Parse() write usage to w and return ErrHelp if
+// GOCFG_HELP environment variable is true or command-line arguments contain
+// --help or -h flag. Arguments are os.Args[1:] or arguments set by SetArgs.
+func (c *Config) EnableHelp(w io.Writer) {
+	c.help = w
+}
+
+// helpRequested returns true if help is requested by GOCFG_HELP variable of
+// the SourceEnv source or --help flag.
+func (c *Config) helpRequested() bool {
+	for _, s := range c.sources {
+		if s.name != SourceEnv {
+			continue
+		}
+		if v, ok := s.lookuper.LookupEnv(HelpEnv); ok {
+			if help, err := strconv.ParseBool(v); err == nil && help {
+				return true
+			}
+		}
+	}
+	args := c.args
+	if args == nil {
+		args = os.Args[1:]
+	}
+	for _, a := range args {
+		if a == "--" {
+			break
+		}
+		switch a {
+		case "-h", "-help", "--help":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package gocfg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newUsageConfig returns config with variables of different kinds.
+func newUsageConfig(env map[string]string) *Config {
+	cfg := New()
+	cfg.SetEnvLookuper(&EnvLookuperMock{vars: env})
+	var apiURL, password, mode string
+	var workers int
+	var brokers []string
+	cfg.SetString(&apiURL, &Variable{
+		Name:           "API_URL",
+		Required:       true,
+		Description:    "API endpoint",
+		ValidationFunc: All(ValidateStringHasPrefix("https"), Not(ValidateStringContains("localhost"))),
+	})
+	cfg.SetString(&password, &Variable{Name: "DB_PASSWORD", Default: "postgres", Sensitive: true})
+	cfg.SetInt(&workers, &Variable{
+		Name:        "WORKERS",
+		Default:     4,
+		Description: "number of workers",
+		Validators:  []func(value interface{}) error{ValidateBetween(1, 16), Any(ValidateMultipleOf(2), ValidateOneOfInts(1))},
+	})
+	cfg.SetStringSlice(&brokers, &Variable{Name: "KAFKA_BROKERS", Default: []string{"a:9092", "b:9092"}, ElementValidationFunc: ValidateHostPort()})
+	cfg.SetString(&mode, &Variable{Name: "MODE", ValidationFunc: func(value interface{}) error {
+		if value.(string) == "" {
+			return errors.New("mode is empty")
+		}
+		return nil
+	}})
+	return cfg
+}
+
+func TestConfigUsage(t *testing.T) {
+	cfg := newUsageConfig(nil)
+	var b bytes.Buffer
+	assert.Nil(t, cfg.Usage(&b))
+	expected := []string{
+		"NAME           TYPE      REQUIRED  DEFAULT        DESCRIPTION        VALIDATION",
+		"API_URL        string    yes                      API endpoint       starts with 'https', not (contains 'localhost')",
+		"DB_PASSWORD    string              ******                            ",
+		"WORKERS        int                 4              number of workers  between 1 and 16, any of (multiple of 2; one of [1])",
+		"KAFKA_BROKERS  []string            a:9092,b:9092                     each host:port",
+		"MODE           string                                                custom",
+		"",
+	}
+	assert.Equal(t, strings.Join(expected, "\n"), b.String())
+}
+
+func TestConfigHelp(t *testing.T) {
+	testcases := []struct {
+		name string
+		env  map[string]string
+		args []string
+		help bool
+	}{
+		{"help env", map[string]string{HelpEnv: "1", "API_URL": "https://api.example.com", "MODE": "fast"}, []string{}, true},
+		{"help flag", map[string]string{"API_URL": "https://api.example.com", "MODE": "fast"}, []string{"--help"}, true},
+		{"short help flag", map[string]string{"API_URL": "https://api.example.com", "MODE": "fast"}, []string{"-h"}, true},
+		{"help after terminator", map[string]string{"API_URL": "https://api.example.com", "MODE": "fast"}, []string{"--", "--help"}, false},
+		{"no help", map[string]string{HelpEnv: "false", "API_URL": "https://api.example.com", "MODE": "fast"}, []string{}, false},
+	}
+	for _, tc := range testcases {
+		cfg := newUsageConfig(tc.env)
+		cfg.SetArgs(tc.args)
+		var b bytes.Buffer
+		cfg.EnableHelp(&b)
+		err := cfg.Parse()
+		if tc.help {
+			assert.Equal(t, ErrHelp, err, fmt.Sprintf("Test case: %s", tc.name))
+			assert.Contains(t, b.String(), "API_URL", fmt.Sprintf("Test case: %s", tc.name))
+			continue
+		}
+		assert.Nil(t, err, fmt.Sprintf("Test case: %s", tc.name))
+		assert.Empty(t, b.String(), fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestDescribeValidators(t *testing.T) {
+	calls := 0
+	custom := func(value interface{}) error {
+		calls++
+		return nil
+	}
+	testcases := []struct {
+		name  string
+		vfunc func(value interface{}) error
+		descs []string
+	}{
+		{"predefined", ValidateStringHasPrefix("https"), []string{"starts with 'https'"}},
+		{"custom", custom, []string{"custom"}},
+		{"described custom", Describe("even", map[string]interface{}{"multipleOf": 2}, custom), []string{"even"}},
+		{"typed", Typed[string](ValidateStringHasPrefix("https")).Untyped(), []string{"custom"}},
+		{"typed custom", ValidationFunc[string](func(value string) error { calls++; return nil }).Untyped(), []string{"custom"}},
+		{"composed", Not(Any(custom, ValidatePositive())), []string{"not (any of (custom; positive))"}},
+	}
+	for _, tc := range testcases {
+		assert.Equal(t, describeValidator(tc.vfunc), tc.descs, fmt.Sprintf("Test case: %s", tc.name))
+	}
+	// validation funcs are not called to describe them
+	assert.Equal(t, 0, calls)
+
+	cfg := New()
+	var apiURL string
+	Set(cfg, &apiURL, VarOpts[string]{
+		Name:           "API_URL",
+		Description:    "API endpoint",
+		ValidationFunc: ValidateStringHasPrefix("https"),
+		Validators: []ValidationFunc[string]{
+			func(value string) error { calls++; return nil },
+		},
+	})
+	var b bytes.Buffer
+	assert.Nil(t, cfg.Usage(&b))
+	assert.Contains(t, b.String(), "API endpoint  starts with 'https', custom")
+	assert.Equal(t, 0, calls)
+}
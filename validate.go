@@ -2,12 +2,17 @@ package gocfg
 
 import (
 	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
 func ValidateStringRegexpMatch(exp string) func(value interface{}) error {
-	return func(value interface{}) error {
+	return Describe(fmt.Sprintf("matches '%s'", exp), map[string]interface{}{"pattern": exp}, func(value interface{}) error {
 		v := value.(string)
 		ok, err := regexp.MatchString(exp, v)
 		if err != nil {
@@ -17,35 +22,335 @@ func ValidateStringRegexpMatch(exp string) func(value interface{}) error {
 			return fmt.Errorf("value '%s' does not match regular expression '%s'", v, exp)
 		}
 		return nil
-	}
+	})
 }
 
 func ValidateStringContains(s string) func(value interface{}) error {
-	return func(value interface{}) error {
+	return Describe(fmt.Sprintf("contains '%s'", s), map[string]interface{}{"pattern": regexp.QuoteMeta(s)}, func(value interface{}) error {
 		v := value.(string)
 		if ok := strings.Contains(v, s); !ok {
 			return fmt.Errorf("value '%s' does not contain '%s'", v, s)
 		}
 		return nil
-	}
+	})
 }
 
 func ValidateStringHasPrefix(s string) func(value interface{}) error {
-	return func(value interface{}) error {
+	return Describe(fmt.Sprintf("starts with '%s'", s), map[string]interface{}{"pattern": "^" + regexp.QuoteMeta(s)}, func(value interface{}) error {
 		v := value.(string)
 		if ok := strings.HasPrefix(v, s); !ok {
 			return fmt.Errorf("value '%s' does not start with '%s'", v, s)
 		}
 		return nil
-	}
+	})
 }
 
 func ValidateStringHasSuffix(s string) func(value interface{}) error {
-	return func(value interface{}) error {
+	return Describe(fmt.Sprintf("ends with '%s'", s), map[string]interface{}{"pattern": regexp.QuoteMeta(s) + "$"}, func(value interface{}) error {
 		v := value.(string)
 		if ok := strings.HasSuffix(v, s); !ok {
 			return fmt.Errorf("value '%s' does not end with '%s'", v, s)
 		}
 		return nil
+	})
+}
+
+// toFloat64 converts numeric value of any integer or float type to float64.
+func toFloat64(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// toInt64 converts numeric value of any integer type or float value without
+// fractional part to int64.
+func toInt64(value interface{}) (int64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(f), true
+	}
+	return 0, false
+}
+
+// ValidateMin checks that numeric value is greater than or equal to min. It
+// accepts values of any integer and float type.
+func ValidateMin(min float64) func(value interface{}) error {
+	return Describe(fmt.Sprintf("min %v", min), map[string]interface{}{"minimum": min}, func(value interface{}) error {
+		v, ok := toFloat64(value)
+		if !ok {
+			return fmt.Errorf("value '%v' is not a number", value)
+		}
+		if v < min {
+			return fmt.Errorf("value '%v' is less than '%v'", value, min)
+		}
+		return nil
+	})
+}
+
+// ValidateMax checks that numeric value is less than or equal to max. It
+// accepts values of any integer and float type.
+func ValidateMax(max float64) func(value interface{}) error {
+	return Describe(fmt.Sprintf("max %v", max), map[string]interface{}{"maximum": max}, func(value interface{}) error {
+		v, ok := toFloat64(value)
+		if !ok {
+			return fmt.Errorf("value '%v' is not a number", value)
+		}
+		if v > max {
+			return fmt.Errorf("value '%v' is greater than '%v'", value, max)
+		}
+		return nil
+	})
+}
+
+// ValidateBetween checks that numeric value is in [min, max] range. It
+// accepts values of any integer and float type.
+func ValidateBetween(min, max float64) func(value interface{}) error {
+	return Describe(fmt.Sprintf("between %v and %v", min, max), map[string]interface{}{"minimum": min, "maximum": max}, func(value interface{}) error {
+		v, ok := toFloat64(value)
+		if !ok {
+			return fmt.Errorf("value '%v' is not a number", value)
+		}
+		if v < min || v > max {
+			return fmt.Errorf("value '%v' is not between '%v' and '%v'", value, min, max)
+		}
+		return nil
+	})
+}
+
+// ValidatePositive checks that numeric value is greater than zero. It
+// accepts values of any integer and float type.
+func ValidatePositive() func(value interface{}) error {
+	return Describe("positive", map[string]interface{}{"exclusiveMinimum": 0}, func(value interface{}) error {
+		v, ok := toFloat64(value)
+		if !ok {
+			return fmt.Errorf("value '%v' is not a number", value)
+		}
+		if v <= 0 {
+			return fmt.Errorf("value '%v' is not positive", value)
+		}
+		return nil
+	})
+}
+
+// ValidateNonZero checks that numeric value is not zero. It accepts values
+// of any integer and float type.
+func ValidateNonZero() func(value interface{}) error {
+	return Describe("non-zero", map[string]interface{}{"not": map[string]interface{}{"const": 0}}, func(value interface{}) error {
+		v, ok := toFloat64(value)
+		if !ok {
+			return fmt.Errorf("value '%v' is not a number", value)
+		}
+		if v == 0 {
+			return fmt.Errorf("value '%v' is zero", value)
+		}
+		return nil
+	})
+}
+
+// ValidateMultipleOf checks that numeric value is a multiple of n. Integer
+// values are checked exactly, float values with a small tolerance.
+func ValidateMultipleOf(n float64) func(value interface{}) error {
+	return Describe(fmt.Sprintf("multiple of %v", n), map[string]interface{}{"multipleOf": n}, func(value interface{}) error {
+		v, ok := toFloat64(value)
+		if !ok {
+			return fmt.Errorf("value '%v' is not a number", value)
+		}
+		iv, isInt := toInt64(value)
+		in, isIntN := toInt64(n)
+		switch {
+		case isInt && isIntN && in != 0:
+			ok = iv%in == 0
+		case n != 0:
+			r := math.Abs(math.Remainder(v, n))
+			ok = r <= 1e-9*math.Abs(n)
+		default:
+			ok = v == 0
+		}
+		if !ok {
+			return fmt.Errorf("value '%v' is not a multiple of '%v'", value, n)
+		}
+		return nil
+	})
+}
+
+// ValidateOneOfInts checks that value is one of the integers. It accepts
+// values of any integer type.
+func ValidateOneOfInts(values ...int) func(value interface{}) error {
+	return Describe(fmt.Sprintf("one of %v", values), map[string]interface{}{"enum": values}, func(value interface{}) error {
+		v, ok := toInt64(value)
+		if ok {
+			for _, e := range values {
+				if int64(e) == v {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("value '%v' is not one of %v", value, values)
+	})
+}
+
+// toURL converts string, Secret, url.URL or *url.URL value to *url.URL.
+func toURL(value interface{}) (*url.URL, error) {
+	switch v := value.(type) {
+	case url.URL:
+		return &v, nil
+	case *url.URL:
+		return v, nil
+	case string:
+		return url.Parse(v)
+	case Secret:
+		return url.Parse(string(v))
 	}
+	return nil, fmt.Errorf("value '%v' is not a URL", value)
+}
+
+// ValidateURLScheme checks that URL scheme is one of schemes. It accepts
+// string, Secret, url.URL and *url.URL values.
+func ValidateURLScheme(schemes ...string) func(value interface{}) error {
+	return Describe(fmt.Sprintf("scheme one of %v", schemes), map[string]interface{}{"pattern": schemesPattern(schemes)}, func(value interface{}) error {
+		u, err := toURL(value)
+		if err != nil {
+			return err
+		}
+		for _, s := range schemes {
+			if strings.EqualFold(u.Scheme, s) {
+				return nil
+			}
+		}
+		return fmt.Errorf("value '%s' scheme is not one of %v", u, schemes)
+	})
+}
+
+// schemesPattern returns regular expression which matches URLs with one of
+// schemes.
+func schemesPattern(schemes []string) string {
+	quoted := make([]string, len(schemes))
+	for i, s := range schemes {
+		quoted[i] = regexp.QuoteMeta(s)
+	}
+	return "^(" + strings.Join(quoted, "|") + "):"
+}
+
+// ValidateHostPort checks that value is a host:port pair with a valid port
+// number. It accepts string and Secret values and hosts of url.URL and
+// *url.URL values.
+func ValidateHostPort() func(value interface{}) error {
+	return Describe("host:port", nil, func(value interface{}) error {
+		var v string
+		switch tv := value.(type) {
+		case string:
+			v = tv
+		case Secret:
+			v = string(tv)
+		case url.URL:
+			v = tv.Host
+		case *url.URL:
+			v = tv.Host
+		default:
+			return fmt.Errorf("value '%v' is not a host:port pair", value)
+		}
+		host, port, err := net.SplitHostPort(v)
+		if err != nil || host == "" {
+			return fmt.Errorf("value '%s' is not a host:port pair", v)
+		}
+		if p, err := strconv.ParseUint(port, 10, 16); err != nil || p == 0 {
+			return fmt.Errorf("value '%s' has a wrong port", v)
+		}
+		return nil
+	})
+}
+
+// ValidatePortRange checks that port is in [min, max] range. It accepts
+// numeric values, host:port strings and URLs with a port.
+func ValidatePortRange(min, max int) func(value interface{}) error {
+	return Describe(fmt.Sprintf("port between %d and %d", min, max), nil, func(value interface{}) error {
+		port, ok := toInt64(value)
+		if !ok {
+			var p string
+			switch v := value.(type) {
+			case string:
+				_, p, _ = net.SplitHostPort(v)
+			case Secret:
+				_, p, _ = net.SplitHostPort(string(v))
+			case url.URL:
+				p = v.Port()
+			case *url.URL:
+				p = v.Port()
+			}
+			var err error
+			if port, err = strconv.ParseInt(p, 10, 64); err != nil {
+				return fmt.Errorf("value '%v' has no port", value)
+			}
+		}
+		if port < int64(min) || port > int64(max) {
+			return fmt.Errorf("port '%d' is not between '%d' and '%d'", port, min, max)
+		}
+		return nil
+	})
+}
+
+// ValidateIP checks that value is an IP address. It accepts string, Secret
+// and net.IP values.
+func ValidateIP() func(value interface{}) error {
+	return Describe("ip address", map[string]interface{}{"anyOf": []interface{}{map[string]interface{}{"format": "ipv4"}, map[string]interface{}{"format": "ipv6"}}}, func(value interface{}) error {
+		var ip net.IP
+		switch v := value.(type) {
+		case string:
+			ip = net.ParseIP(v)
+		case Secret:
+			ip = net.ParseIP(string(v))
+		case net.IP:
+			if len(v) == net.IPv4len || len(v) == net.IPv6len {
+				ip = v
+			}
+		}
+		if ip == nil {
+			return fmt.Errorf("value '%v' is not an IP address", value)
+		}
+		return nil
+	})
+}
+
+// ValidateCIDR checks that value is an IP network in CIDR notation. It
+// accepts string, Secret, net.IPNet and *net.IPNet values.
+func ValidateCIDR() func(value interface{}) error {
+	return Describe("cidr", nil, func(value interface{}) error {
+		var n *net.IPNet
+		switch v := value.(type) {
+		case string:
+			_, n, _ = net.ParseCIDR(v)
+		case Secret:
+			_, n, _ = net.ParseCIDR(string(v))
+		case net.IPNet:
+			n = &v
+		case *net.IPNet:
+			n = v
+		}
+		if n == nil || n.IP == nil {
+			return fmt.Errorf("value '%v' is not a CIDR", value)
+		}
+		if _, bits := n.Mask.Size(); bits == 0 {
+			return fmt.Errorf("value '%v' is not a CIDR", value)
+		}
+		return nil
+	})
 }
@@ -3,6 +3,8 @@ package gocfg
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,3 +31,92 @@ func TestValidateStringFuncs(t *testing.T) {
 		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s", tc.name))
 	}
 }
+
+func TestValidateNumberFuncs(t *testing.T) {
+	testcases := []struct {
+		name  string
+		value interface{}
+		vfunc func(value interface{}) error
+		err   error
+	}{
+		{"check min func int", 10, ValidateMin(1), nil},
+		{"check min func float32 as float64", float64(float32(0.5)), ValidateMin(0.5), nil},
+		{"check min func failed", int64(0), ValidateMin(1), errors.New("value '0' is less than '1'")},
+		{"check min func not a number", "10", ValidateMin(1), errors.New("value '10' is not a number")},
+		{"check max func uint16", uint16(8080), ValidateMax(65535), nil},
+		{"check max func failed", 2.5, ValidateMax(2), errors.New("value '2.5' is greater than '2'")},
+		{"check between func", float32(0.3), ValidateBetween(0, 1), nil},
+		{"check between func failed", int8(-1), ValidateBetween(0, 1), errors.New("value '-1' is not between '0' and '1'")},
+		{"check positive func", uint(1), ValidatePositive(), nil},
+		{"check positive func failed", 0, ValidatePositive(), errors.New("value '0' is not positive")},
+		{"check non zero func", -1, ValidateNonZero(), nil},
+		{"check non zero func failed", 0.0, ValidateNonZero(), errors.New("value '0' is zero")},
+		{"check multiple of func int", int64(30), ValidateMultipleOf(10), nil},
+		{"check multiple of func float", 0.3, ValidateMultipleOf(0.1), nil},
+		{"check multiple of func failed", 25, ValidateMultipleOf(10), errors.New("value '25' is not a multiple of '10'")},
+		{"check multiple of func float failed", 0.35, ValidateMultipleOf(0.1), errors.New("value '0.35' is not a multiple of '0.1'")},
+		{"check one of ints func", uint8(2), ValidateOneOfInts(1, 2, 4), nil},
+		{"check one of ints func integral float", 4.0, ValidateOneOfInts(1, 2, 4), nil},
+		{"check one of ints func failed", int32(3), ValidateOneOfInts(1, 2, 4), errors.New("value '3' is not one of [1 2 4]")},
+		{"check one of ints func fractional float", 2.5, ValidateOneOfInts(1, 2, 4), errors.New("value '2.5' is not one of [1 2 4]")},
+	}
+	for _, tc := range testcases {
+		err := tc.vfunc(tc.value)
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}
+
+func TestValidateNumberFuncsWithConfig(t *testing.T) {
+	cfg := New()
+	cfg.SetEnvLookuper(&EnvLookuperMock{vars: map[string]string{"CPU_LIMIT": "1.5", "WORKERS": "3"}})
+	var cpuLimit float32
+	var workers uint
+	cfg.SetFloat32(&cpuLimit, &Variable{Name: "CPU_LIMIT", ValidationFunc: ValidateBetween(0.1, 1)})
+	cfg.SetUint(&workers, &Variable{Name: "WORKERS", ValidationFunc: ValidateMultipleOf(2)})
+	err := cfg.Parse()
+	assert.Equal(t, NewParseErrors(
+		&ValidationError{Name: "CPU_LIMIT", Value: "1.5", Err: errors.New("value '1.5' is not between '0.1' and '1'")},
+		&ValidationError{Name: "WORKERS", Value: "3", Err: errors.New("value '3' is not a multiple of '2'")},
+	), err)
+}
+
+func TestValidateNetworkFuncs(t *testing.T) {
+	apiURL, _ := url.Parse("https://api.example.com:8443")
+	_, trusted, _ := net.ParseCIDR("10.0.0.0/8")
+	testcases := []struct {
+		name  string
+		value interface{}
+		vfunc func(value interface{}) error
+		err   error
+	}{
+		{"check url scheme func", "https://api.example.com", ValidateURLScheme("https"), nil},
+		{"check url scheme func url", *apiURL, ValidateURLScheme("http", "https"), nil},
+		{"check url scheme func failed", apiURL, ValidateURLScheme("http"), errors.New("value 'https://api.example.com:8443' scheme is not one of [http]")},
+		{"check url scheme func not a url", 1, ValidateURLScheme("http"), errors.New("value '1' is not a URL")},
+		{"check host port func", "db.example.com:5432", ValidateHostPort(), nil},
+		{"check host port func ipv6", "[::1]:5432", ValidateHostPort(), nil},
+		{"check host port func no port", "db.example.com", ValidateHostPort(), errors.New("value 'db.example.com' is not a host:port pair")},
+		{"check host port func wrong port", "db.example.com:70000", ValidateHostPort(), errors.New("value 'db.example.com:70000' has a wrong port")},
+		{"check host port func url", apiURL, ValidateHostPort(), nil},
+		{"check host port func secret", Secret("db.example.com:5432"), ValidateHostPort(), nil},
+		{"check host port func wrong type", 5432, ValidateHostPort(), errors.New("value '5432' is not a host:port pair")},
+		{"check port range func", uint16(8080), ValidatePortRange(1024, 65535), nil},
+		{"check port range func host port", "localhost:80", ValidatePortRange(1024, 65535), errors.New("port '80' is not between '1024' and '65535'")},
+		{"check port range func url", apiURL, ValidatePortRange(8000, 9000), nil},
+		{"check port range func no port", "localhost", ValidatePortRange(1, 65535), errors.New("value 'localhost' has no port")},
+		{"check ip func", "10.0.0.1", ValidateIP(), nil},
+		{"check ip func failed", "10.0.0", ValidateIP(), errors.New("value '10.0.0' is not an IP address")},
+		{"check ip func net ip", net.ParseIP("::1"), ValidateIP(), nil},
+		{"check ip func empty net ip", net.IP{}, ValidateIP(), errors.New("value '<nil>' is not an IP address")},
+		{"check ip func wrong type", 1, ValidateIP(), errors.New("value '1' is not an IP address")},
+		{"check cidr func", "10.0.0.0/8", ValidateCIDR(), nil},
+		{"check cidr func failed", "10.0.0.0", ValidateCIDR(), errors.New("value '10.0.0.0' is not a CIDR")},
+		{"check cidr func net ipnet", *trusted, ValidateCIDR(), nil},
+		{"check cidr func net ipnet pointer", trusted, ValidateCIDR(), nil},
+		{"check cidr func wrong type", 1, ValidateCIDR(), errors.New("value '1' is not a CIDR")},
+	}
+	for _, tc := range testcases {
+		err := tc.vfunc(tc.value)
+		assert.Equal(t, err, tc.err, fmt.Sprintf("Test case: %s", tc.name))
+	}
+}